@@ -1,57 +1,48 @@
 package middlewares
 
 import (
-	"net/http"
 	"strings"
 
-	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token and sets user info in context
-func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
-			c.Abort()
-			return
-		}
-
-		// Extract token from "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization format"})
-			c.Abort()
-			return
-		}
+// AuthMiddleware validates JWT token against the active sessions and sets user info in context
+type AuthMiddleware struct {
+	authService auth.Service
+}
 
-		token := parts[1]
+// NewAuthMiddleware creates a new auth middleware
+func NewAuthMiddleware(authService auth.Service) *AuthMiddleware {
+	return &AuthMiddleware{authService: authService}
+}
 
-		// Validate token
-		claims, err := utils.ValidateToken(token)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+// Handle rejects requests without a valid token of a live session
+func (m *AuthMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := m.authenticate(c); err != nil {
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
 
-		// Set user info in context
-		c.Set("userID", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
-
 		c.Next()
 	}
 }
 
-// MerchantMiddleware ensures the user is a merchant
-func MerchantMiddleware() gin.HandlerFunc {
+// Optional sets user info when a token is sent but lets anonymous requests
+// through. An invalid token is still rejected.
+func (m *AuthMiddleware) Optional() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "merchant" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "merchant access required"})
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		if err := m.authenticate(c); err != nil {
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
@@ -60,16 +51,31 @@ func MerchantMiddleware() gin.HandlerFunc {
 	}
 }
 
-// AdminMiddleware ensures the user is an admin
-func AdminMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
-			c.Abort()
-			return
-		}
+// authenticate validates the bearer token and sets user info in context
+func (m *AuthMiddleware) authenticate(c *gin.Context) error {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return apperror.Unauthorized("authorization_required", "authorization header required")
+	}
 
-		c.Next()
+	// Extract token from "Bearer <token>"
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return apperror.Unauthorized("invalid_authorization", "invalid authorization format")
+	}
+
+	token := parts[1]
+
+	// Validate token and reject it if its session was logged out or revoked
+	user, err := m.authService.ValidateToken(token)
+	if err != nil {
+		return err
 	}
+
+	// Set user info in context
+	c.Set("userID", user.ID)
+	c.Set("email", user.Email)
+	c.Set("role", user.Role)
+
+	return nil
 }
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+)
+
+// ErrorMiddleware renders errors attached to the gin context as JSON
+type ErrorMiddleware struct {
+	handler lib.RequestHandler
+	logger  lib.Logger
+}
+
+// statusByKind maps error kinds to HTTP status codes
+var statusByKind = map[apperror.Kind]int{
+	apperror.KindBadRequest:        http.StatusBadRequest,
+	apperror.KindUnauthorized:      http.StatusUnauthorized,
+	apperror.KindForbidden:         http.StatusForbidden,
+	apperror.KindNotFound:          http.StatusNotFound,
+	apperror.KindConflict:          http.StatusConflict,
+	apperror.KindValidation:        http.StatusUnprocessableEntity,
+	apperror.KindInsufficientStock: http.StatusConflict,
+	apperror.KindTooManyRequests:   http.StatusTooManyRequests,
+	apperror.KindInternal:          http.StatusInternalServerError,
+}
+
+// NewErrorMiddleware creates new error middleware
+func NewErrorMiddleware(handler lib.RequestHandler, logger lib.Logger) ErrorMiddleware {
+	return ErrorMiddleware{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// Setup sets up error middleware
+func (m ErrorMiddleware) Setup() {
+	m.logger.Info("Setting up error middleware")
+
+	m.handler.Gin.Use(func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		appErr := apperror.From(c.Errors.Last().Err)
+		if appErr.Kind == apperror.KindInternal {
+			m.logger.Error(appErr)
+		}
+
+		status, ok := statusByKind[appErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+
+		c.JSON(status, gin.H{
+			"error": appErr.Message,
+			"code":  appErr.Code,
+		})
+	})
+}
@@ -1,51 +1,55 @@
 package middlewares
 
 import (
-	"net/http"
+	"errors"
 
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
 
 	"github.com/gin-gonic/gin"
 )
 
-// MerchantContextMiddleware fetches merchant ID and adds it to context
+var errMerchantRequired = apperror.Forbidden("merchant_required", "merchant access required")
+
+// MerchantContextMiddleware fetches the shop the user works for and adds its
+// ID and the user's role in the shop to context
 type MerchantContextMiddleware struct {
-	merchantService merchant.Service
+	staffService merchant.StaffService
 }
 
 // NewMerchantContextMiddleware creates a new merchant context middleware
-func NewMerchantContextMiddleware(merchantService merchant.Service) *MerchantContextMiddleware {
+func NewMerchantContextMiddleware(staffService merchant.StaffService) *MerchantContextMiddleware {
 	return &MerchantContextMiddleware{
-		merchantService: merchantService,
+		staffService: staffService,
 	}
 }
 
-// Handle adds merchant ID to context if user is a merchant
+// Handle adds the merchant ID and shop role to context. Users who are not an
+// active member of a shop are rejected.
 func (m *MerchantContextMiddleware) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "merchant" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "merchant access required"})
-			c.Abort()
-			return
-		}
-
 		userID, exists := c.Get("userID")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			_ = c.Error(auth.ErrUnauthenticated)
 			c.Abort()
 			return
 		}
 
-		// Get merchant profile
-		merch, err := m.merchantService.GetMerchantByUserID(userID.(uint))
+		// Membership is checked on every request, so a revoked member loses
+		// access at once
+		member, err := m.staffService.GetMembership(userID.(uint))
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "merchant profile not found"})
+			if errors.Is(err, merchant.ErrMemberNotFound) {
+				err = errMerchantRequired
+			}
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
 
-		c.Set("merchantID", merch.ID)
+		c.Set("merchantID", member.MerchantID)
+		c.Set("merchantRole", member.Role)
 		c.Next()
 	}
 }
@@ -7,6 +7,8 @@ var Module = fx.Options(
 	fx.Provide(NewCorsMiddleware),
 	fx.Provide(NewJWTAuthMiddleware),
 	fx.Provide(NewDatabaseTrx),
+	fx.Provide(NewErrorMiddleware),
+	fx.Provide(NewAuthMiddleware),
 	fx.Provide(NewMiddlewares),
 )
 
@@ -23,10 +25,12 @@ type Middlewares []IMiddleware
 func NewMiddlewares(
 	corsMiddleware CorsMiddleware,
 	dbTrxMiddleware DatabaseTrx,
+	errorMiddleware ErrorMiddleware,
 ) Middlewares {
 	return Middlewares{
 		corsMiddleware,
 		dbTrxMiddleware,
+		errorMiddleware,
 	}
 }
 
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission ensures the user's role, or their role in the shop set
+// by MerchantContextMiddleware, grants the permission. It must run after
+// AuthMiddleware.
+func RequirePermission(permission rbac.Permission) gin.HandlerFunc {
+	if !rbac.Known(permission) {
+		panic("unknown permission " + string(permission))
+	}
+
+	return func(c *gin.Context) {
+		if !rbac.Allows(c.GetString("role"), permission) && !rbac.Allows(c.GetString("merchantRole"), permission) {
+			_ = c.Error(rbac.ErrPermissionDenied.WithMessage("missing permission " + string(permission)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
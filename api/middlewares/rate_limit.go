@@ -0,0 +1,100 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrRateLimited is returned to callers that exceed a rate limit
+var ErrRateLimited = apperror.TooManyRequests("rate_limited", "too many requests, please slow down")
+
+// RateLimit allows each caller limit requests per period, refilled evenly
+// over the period. Callers are the authenticated user, or the client IP on
+// routes without AuthMiddleware. Counters live in this process only.
+func RateLimit(limit int, period time.Duration) gin.HandlerFunc {
+	limiter := newRateLimiter(limit, period)
+
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if userID, ok := c.Get("userID"); ok {
+			key = fmt.Sprintf("user:%v", userID)
+		}
+
+		if wait, ok := limiter.allow(key, time.Now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			_ = c.Error(ErrRateLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimiter is a token bucket per caller
+type rateLimiter struct {
+	mu        sync.Mutex
+	burst     float64
+	perToken  time.Duration
+	buckets   map[string]*bucket
+	lastPrune time.Time
+	period    time.Duration
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit int, period time.Duration) *rateLimiter {
+	return &rateLimiter{
+		burst:    float64(limit),
+		perToken: period / time.Duration(limit),
+		buckets:  make(map[string]*bucket),
+		period:   period,
+	}
+}
+
+// allow takes a token from the caller's bucket, or reports how long until
+// the next token when it is empty
+func (l *rateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+float64(now.Sub(b.last))/float64(l.perToken))
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) * float64(l.perToken)), false
+	}
+	b.tokens--
+	return 0, true
+}
+
+// prune drops the buckets that refilled completely, at most once a period,
+// so idle callers do not pile up
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.period {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.period {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter := newRateLimiter(3, time.Minute)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if _, ok := limiter.allow("user:1", now); !ok {
+			t.Fatalf("request %d was limited within the burst", i+1)
+		}
+	}
+
+	wait, ok := limiter.allow("user:1", now)
+	if ok {
+		t.Fatal("request over the limit was allowed")
+	}
+	if wait != 20*time.Second {
+		t.Errorf("wait = %v, want 20s", wait)
+	}
+
+	if _, ok := limiter.allow("user:2", now); !ok {
+		t.Error("another caller was limited")
+	}
+
+	if _, ok := limiter.allow("user:1", now.Add(20*time.Second)); !ok {
+		t.Error("request after a refill was limited")
+	}
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
+)
+
+// AdminRoutes struct
+type AdminRoutes struct {
+	handler        *handlers.AdminHandler
+	requestHandler lib.RequestHandler
+	authMiddleware *middlewares.AuthMiddleware
+}
+
+// Setup admin routes
+func (r AdminRoutes) Setup() {
+	admin := r.requestHandler.Gin.Group("/api/admin")
+	admin.Use(r.authMiddleware.Handle())
+	{
+		admin.GET("/stats", middlewares.RequirePermission(rbac.StatsRead), r.handler.GetStats)
+		admin.GET("/audit-logs", middlewares.RequirePermission(rbac.AuditRead), r.handler.GetAuditLogs)
+
+		admin.GET("/users", middlewares.RequirePermission(rbac.UsersManage), r.handler.ListUsers)
+		admin.POST("/users/:id/deactivate", middlewares.RequirePermission(rbac.UsersManage), r.handler.DeactivateUser)
+		admin.POST("/users/:id/activate", middlewares.RequirePermission(rbac.UsersManage), r.handler.ActivateUser)
+
+		admin.GET("/merchants", middlewares.RequirePermission(rbac.MerchantsList), r.handler.ListMerchants)
+
+		admin.GET("/orders", middlewares.RequirePermission(rbac.OrdersModerate), r.handler.ListOrders)
+		admin.POST("/orders/:id/cancel", middlewares.RequirePermission(rbac.OrdersModerate), r.handler.CancelOrder)
+
+		admin.POST("/products/:id/unlist", middlewares.RequirePermission(rbac.ProductsModerate), r.handler.UnlistProduct)
+		admin.POST("/products/:id/relist", middlewares.RequirePermission(rbac.ProductsModerate), r.handler.RelistProduct)
+	}
+}
+
+// NewAdminRoutes creates new admin routes
+func NewAdminRoutes(
+	handler *handlers.AdminHandler,
+	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+) AdminRoutes {
+	return AdminRoutes{
+		handler:        handler,
+		requestHandler: requestHandler,
+		authMiddleware: authMiddleware,
+	}
+}
@@ -13,6 +13,7 @@ type AuthRoutes struct {
 	handler        lib.RequestHandler
 	authController controllers.JWTAuthController
 	authHandler    *handlers.AuthHandler
+	authMiddleware *middlewares.AuthMiddleware
 }
 
 // Setup user routes
@@ -31,12 +32,14 @@ func (s AuthRoutes) Setup() {
 	{
 		api.POST("/register", s.authHandler.Register)
 		api.POST("/login", s.authHandler.Login)
+		api.POST("/refresh", s.authHandler.Refresh)
 
 		// Protected routes
 		protected := api.Group("")
-		protected.Use(middlewares.AuthMiddleware())
+		protected.Use(s.authMiddleware.Handle())
 		{
 			protected.POST("/logout", s.authHandler.Logout)
+			protected.POST("/logout-all", s.authHandler.LogoutAll)
 			protected.GET("/profile", s.authHandler.GetProfile)
 			protected.PUT("/profile", s.authHandler.UpdateProfile)
 		}
@@ -48,6 +51,7 @@ func NewAuthRoutes(
 	handler lib.RequestHandler,
 	authController controllers.JWTAuthController,
 	authHandler *handlers.AuthHandler,
+	authMiddleware *middlewares.AuthMiddleware,
 	logger lib.Logger,
 ) AuthRoutes {
 	return AuthRoutes{
@@ -55,5 +59,6 @@ func NewAuthRoutes(
 		logger:         logger,
 		authController: authController,
 		authHandler:    authHandler,
+		authMiddleware: authMiddleware,
 	}
 }
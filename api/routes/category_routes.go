@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
+)
+
+// CategoryRoutes struct
+type CategoryRoutes struct {
+	handler        *handlers.CategoryHandler
+	requestHandler lib.RequestHandler
+	authMiddleware *middlewares.AuthMiddleware
+}
+
+// Setup category routes
+func (r CategoryRoutes) Setup() {
+	api := r.requestHandler.Gin.Group("/api")
+	{
+		// Public routes
+		api.GET("/categories", r.handler.GetCategories)
+
+		// Admin routes
+		admin := api.Group("/admin")
+		admin.Use(r.authMiddleware.Handle())
+		admin.Use(middlewares.RequirePermission(rbac.CategoriesManage))
+		{
+			admin.GET("/categories", r.handler.GetAllCategories)
+			admin.POST("/categories", r.handler.CreateCategory)
+			admin.PUT("/categories/:id", r.handler.UpdateCategory)
+			admin.DELETE("/categories/:id", r.handler.DeleteCategory)
+		}
+	}
+}
+
+// NewCategoryRoutes creates new category routes
+func NewCategoryRoutes(
+	handler *handlers.CategoryHandler,
+	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+) CategoryRoutes {
+	return CategoryRoutes{
+		handler:        handler,
+		requestHandler: requestHandler,
+		authMiddleware: authMiddleware,
+	}
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
+)
+
+// GeocodeRoutes struct
+type GeocodeRoutes struct {
+	handler        *handlers.GeocodeHandler
+	requestHandler lib.RequestHandler
+	authMiddleware *middlewares.AuthMiddleware
+}
+
+// Setup geocode routes. Lookups may be forwarded to an external geocoder,
+// so they need a user and are rate limited per user.
+func (r GeocodeRoutes) Setup() {
+	api := r.requestHandler.Gin.Group("/api")
+	api.Use(r.authMiddleware.Handle(), middlewares.RateLimit(30, time.Minute))
+	{
+		api.GET("/geocode", r.handler.Geocode)
+	}
+}
+
+// NewGeocodeRoutes creates new geocode routes
+func NewGeocodeRoutes(
+	handler *handlers.GeocodeHandler,
+	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+) GeocodeRoutes {
+	return GeocodeRoutes{
+		handler:        handler,
+		requestHandler: requestHandler,
+		authMiddleware: authMiddleware,
+	}
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
+)
+
+// LocationRoutes struct
+type LocationRoutes struct {
+	handler        *handlers.LocationHandler
+	requestHandler lib.RequestHandler
+	authMiddleware *middlewares.AuthMiddleware
+}
+
+// Setup location routes
+func (r LocationRoutes) Setup() {
+	api := r.requestHandler.Gin.Group("/api/locations")
+	api.Use(r.authMiddleware.Handle())
+	{
+		api.GET("", r.handler.GetLocations)
+		api.POST("", r.handler.AddLocation)
+		api.PUT("/:id", r.handler.UpdateLocation)
+		api.DELETE("/:id", r.handler.DeleteLocation)
+		api.POST("/:id/default", r.handler.SetDefaultLocation)
+	}
+}
+
+// NewLocationRoutes creates new location routes
+func NewLocationRoutes(
+	handler *handlers.LocationHandler,
+	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+) LocationRoutes {
+	return LocationRoutes{
+		handler:        handler,
+		requestHandler: requestHandler,
+		authMiddleware: authMiddleware,
+	}
+}
@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/media"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/infrastructure/storage"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MediaRoutes serves the files of the local storage
+type MediaRoutes struct {
+	env            lib.Env
+	requestHandler lib.RequestHandler
+}
+
+// Setup media routes. Files in an object store are served by the store.
+func (r MediaRoutes) Setup() {
+	provider := strings.ToLower(r.env.StorageProvider)
+	if provider != "" && provider != "local" {
+		return
+	}
+
+	dir, publicURL := storage.LocalSettings(r.env)
+	if strings.HasPrefix(publicURL, "/") {
+		r.requestHandler.Gin.StaticFS(publicURL, publicFiles{gin.Dir(dir, false)})
+	}
+}
+
+// publicFiles hides the private files of the local storage
+type publicFiles struct {
+	http.FileSystem
+}
+
+// Open opens a file unless it is private
+func (f publicFiles) Open(name string) (http.File, error) {
+	if strings.HasPrefix(strings.TrimPrefix(name, "/"), media.PrivatePrefix) {
+		return nil, os.ErrNotExist
+	}
+	return f.FileSystem.Open(name)
+}
+
+// NewMediaRoutes creates new media routes
+func NewMediaRoutes(env lib.Env, requestHandler lib.RequestHandler) MediaRoutes {
+	return MediaRoutes{
+		env:            env,
+		requestHandler: requestHandler,
+	}
+}
@@ -2,14 +2,17 @@ package routes
 
 import (
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
 	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
 )
 
 // MerchantRoutes struct
 type MerchantRoutes struct {
-	handler        *handlers.MerchantHandler
-	requestHandler lib.RequestHandler
+	handler                   *handlers.MerchantHandler
+	requestHandler            lib.RequestHandler
+	authMiddleware            *middlewares.AuthMiddleware
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware
 }
 
 // Setup merchant routes
@@ -22,11 +25,11 @@ func (r MerchantRoutes) Setup() {
 
 		// Protected routes
 		auth := api.Group("")
-		auth.Use(middlewares.AuthMiddleware())
-		auth.Use(middlewares.MerchantMiddleware())
+		auth.Use(r.authMiddleware.Handle())
+		auth.Use(r.merchantContextMiddleware.Handle())
 		{
-			auth.GET("/profile", r.handler.GetMerchantProfile)
-			auth.PUT("/profile", r.handler.UpdateMerchantProfile)
+			auth.GET("/profile", middlewares.RequirePermission(rbac.ShopRead), r.handler.GetMerchantProfile)
+			auth.PUT("/profile", middlewares.RequirePermission(rbac.ShopManage), r.handler.UpdateMerchantProfile)
 		}
 	}
 }
@@ -35,9 +38,13 @@ func (r MerchantRoutes) Setup() {
 func NewMerchantRoutes(
 	handler *handlers.MerchantHandler,
 	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware,
 ) MerchantRoutes {
 	return MerchantRoutes{
-		handler:        handler,
-		requestHandler: requestHandler,
+		handler:                   handler,
+		requestHandler:            requestHandler,
+		authMiddleware:            authMiddleware,
+		merchantContextMiddleware: merchantContextMiddleware,
 	}
 }
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
+)
+
+// MerchantStaffRoutes struct
+type MerchantStaffRoutes struct {
+	handler                   *handlers.MerchantStaffHandler
+	requestHandler            lib.RequestHandler
+	authMiddleware            *middlewares.AuthMiddleware
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware
+}
+
+// Setup merchant staff routes
+func (r MerchantStaffRoutes) Setup() {
+	members := r.requestHandler.Gin.Group("/api/merchant/members")
+	members.Use(r.authMiddleware.Handle())
+	members.Use(r.merchantContextMiddleware.Handle())
+	members.Use(middlewares.RequirePermission(rbac.StaffManage))
+	{
+		members.GET("", r.handler.GetMembers)
+		members.POST("", r.handler.InviteMember)
+		members.PUT("/:id", r.handler.UpdateMember)
+		members.DELETE("/:id", r.handler.RevokeMember)
+	}
+
+	// Invitations are answered by users who do not work for a shop yet
+	invitations := r.requestHandler.Gin.Group("/api/invitations")
+	invitations.Use(r.authMiddleware.Handle())
+	{
+		invitations.GET("", r.handler.GetInvitations)
+		invitations.POST("/:id/accept", r.handler.AcceptInvitation)
+		invitations.POST("/:id/decline", r.handler.DeclineInvitation)
+	}
+}
+
+// NewMerchantStaffRoutes creates new merchant staff routes
+func NewMerchantStaffRoutes(
+	handler *handlers.MerchantStaffHandler,
+	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware,
+) MerchantStaffRoutes {
+	return MerchantStaffRoutes{
+		handler:                   handler,
+		requestHandler:            requestHandler,
+		authMiddleware:            authMiddleware,
+		merchantContextMiddleware: merchantContextMiddleware,
+	}
+}
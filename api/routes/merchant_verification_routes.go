@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
+)
+
+// MerchantVerificationRoutes struct
+type MerchantVerificationRoutes struct {
+	handler                   *handlers.MerchantVerificationHandler
+	requestHandler            lib.RequestHandler
+	authMiddleware            *middlewares.AuthMiddleware
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware
+}
+
+// Setup merchant verification routes
+func (r MerchantVerificationRoutes) Setup() {
+	merchant := r.requestHandler.Gin.Group("/api/merchant")
+	merchant.Use(r.authMiddleware.Handle())
+	merchant.Use(r.merchantContextMiddleware.Handle())
+	{
+		merchant.POST("/documents", middlewares.RequirePermission(rbac.ShopManage), r.handler.UploadDocument)
+		merchant.GET("/verification", middlewares.RequirePermission(rbac.ShopRead), r.handler.GetVerification)
+		merchant.POST("/verification/submit", middlewares.RequirePermission(rbac.ShopManage), r.handler.SubmitForReview)
+	}
+
+	admin := r.requestHandler.Gin.Group("/api/admin/merchants")
+	admin.Use(r.authMiddleware.Handle())
+	admin.Use(middlewares.RequirePermission(rbac.MerchantsReview))
+	{
+		admin.GET("/pending", r.handler.GetPendingMerchants)
+		admin.GET("/:id/documents/:documentId", r.handler.GetDocument)
+		admin.POST("/:id/approve", r.handler.ApproveMerchant)
+		admin.POST("/:id/reject", r.handler.RejectMerchant)
+	}
+}
+
+// NewMerchantVerificationRoutes creates new merchant verification routes
+func NewMerchantVerificationRoutes(
+	handler *handlers.MerchantVerificationHandler,
+	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware,
+) MerchantVerificationRoutes {
+	return MerchantVerificationRoutes{
+		handler:                   handler,
+		requestHandler:            requestHandler,
+		authMiddleware:            authMiddleware,
+		merchantContextMiddleware: merchantContextMiddleware,
+	}
+}
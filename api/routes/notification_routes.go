@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
+)
+
+// NotificationRoutes struct
+type NotificationRoutes struct {
+	handler        *handlers.NotificationHandler
+	requestHandler lib.RequestHandler
+	authMiddleware *middlewares.AuthMiddleware
+}
+
+// Setup notification routes
+func (r NotificationRoutes) Setup() {
+	api := r.requestHandler.Gin.Group("/api/notifications")
+	api.Use(r.authMiddleware.Handle())
+	{
+		api.GET("", r.handler.GetNotifications)
+		api.POST("/:id/read", r.handler.MarkRead)
+	}
+}
+
+// NewNotificationRoutes creates new notification routes
+func NewNotificationRoutes(
+	handler *handlers.NotificationHandler,
+	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+) NotificationRoutes {
+	return NotificationRoutes{
+		handler:        handler,
+		requestHandler: requestHandler,
+		authMiddleware: authMiddleware,
+	}
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
 	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
 )
@@ -10,18 +11,21 @@ import (
 type OrderRoutes struct {
 	handler                   *handlers.OrderHandler
 	requestHandler            lib.RequestHandler
+	authMiddleware            *middlewares.AuthMiddleware
 	merchantContextMiddleware *middlewares.MerchantContextMiddleware
 }
 
 // Setup order routes
 func (r OrderRoutes) Setup() {
 	api := r.requestHandler.Gin.Group("/api")
-	api.Use(middlewares.AuthMiddleware())
+	api.Use(r.authMiddleware.Handle())
 	{
 		// Customer routes
 		api.POST("/orders", r.handler.CreateOrder)
 		api.GET("/orders", r.handler.GetUserOrders)
 		api.GET("/orders/:id", r.handler.GetOrder)
+		api.GET("/orders/:id/qr", r.handler.GetOrderQR)
+		api.POST("/orders/:id/cancel", r.handler.CancelOrder)
 
 		// Cart routes
 		api.GET("/cart", r.handler.GetCart)
@@ -29,13 +33,17 @@ func (r OrderRoutes) Setup() {
 		api.PUT("/cart/items/:id", r.handler.UpdateCartItem)
 		api.DELETE("/cart/items/:id", r.handler.RemoveCartItem)
 		api.POST("/cart/clear", r.handler.ClearCart)
+		api.POST("/cart/checkout", r.handler.Checkout)
 
 		// Merchant routes
 		merchant := api.Group("/merchant")
 		merchant.Use(r.merchantContextMiddleware.Handle())
 		{
-			merchant.GET("/orders", r.handler.GetMerchantOrders)
-			merchant.POST("/orders/redeem", r.handler.RedeemOrder)
+			merchant.GET("/orders", middlewares.RequirePermission(rbac.OrdersRead), r.handler.GetMerchantOrders)
+			merchant.POST("/orders/redeem", middlewares.RequirePermission(rbac.OrdersRedeem), r.handler.RedeemOrder)
+			merchant.POST("/orders/:id/confirm", middlewares.RequirePermission(rbac.OrdersManage), r.handler.ConfirmOrder)
+			merchant.POST("/orders/:id/ready", middlewares.RequirePermission(rbac.OrdersManage), r.handler.MarkOrderReady)
+			merchant.POST("/orders/:id/reject", middlewares.RequirePermission(rbac.OrdersManage), r.handler.RejectOrder)
 		}
 	}
 }
@@ -44,11 +52,13 @@ func (r OrderRoutes) Setup() {
 func NewOrderRoutes(
 	handler *handlers.OrderHandler,
 	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
 	merchantContextMiddleware *middlewares.MerchantContextMiddleware,
 ) OrderRoutes {
 	return OrderRoutes{
 		handler:                   handler,
 		requestHandler:            requestHandler,
+		authMiddleware:            authMiddleware,
 		merchantContextMiddleware: merchantContextMiddleware,
 	}
 }
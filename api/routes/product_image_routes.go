@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
+)
+
+// ProductImageRoutes struct
+type ProductImageRoutes struct {
+	handler                   *handlers.ProductImageHandler
+	requestHandler            lib.RequestHandler
+	authMiddleware            *middlewares.AuthMiddleware
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware
+}
+
+// Setup product image routes
+func (r ProductImageRoutes) Setup() {
+	merchant := r.requestHandler.Gin.Group("/api/merchant")
+	merchant.Use(r.authMiddleware.Handle())
+	merchant.Use(r.merchantContextMiddleware.Handle())
+	merchant.Use(middlewares.RequirePermission(rbac.ProductsWrite))
+	{
+		merchant.POST("/products/:id/images", r.handler.UploadImage)
+		merchant.PUT("/products/:id/images", r.handler.ReorderImages)
+		merchant.PUT("/products/:id/images/:imageId", r.handler.UpdateImage)
+		merchant.DELETE("/products/:id/images/:imageId", r.handler.DeleteImage)
+	}
+}
+
+// NewProductImageRoutes creates new product image routes
+func NewProductImageRoutes(
+	handler *handlers.ProductImageHandler,
+	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware,
+) ProductImageRoutes {
+	return ProductImageRoutes{
+		handler:                   handler,
+		requestHandler:            requestHandler,
+		authMiddleware:            authMiddleware,
+		merchantContextMiddleware: merchantContextMiddleware,
+	}
+}
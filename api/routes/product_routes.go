@@ -2,14 +2,17 @@ package routes
 
 import (
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
 	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
 )
 
 // ProductRoutes struct
 type ProductRoutes struct {
-	handler        *handlers.ProductHandler
-	requestHandler lib.RequestHandler
+	handler                   *handlers.ProductHandler
+	requestHandler            lib.RequestHandler
+	authMiddleware            *middlewares.AuthMiddleware
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware
 }
 
 // Setup product routes
@@ -17,18 +20,21 @@ func (r ProductRoutes) Setup() {
 	api := r.requestHandler.Gin.Group("/api")
 	{
 		// Public routes
-		api.GET("/products/search", r.handler.SearchProducts)
+		api.GET("/products/search", r.authMiddleware.Optional(), r.handler.SearchProducts)
 		api.GET("/products/:id", r.handler.GetProduct)
 
 		// Merchant routes (requires authentication + merchant role)
 		merchant := api.Group("/merchant")
-		merchant.Use(middlewares.AuthMiddleware())
-		merchant.Use(middlewares.MerchantMiddleware())
+		merchant.Use(r.authMiddleware.Handle())
+		merchant.Use(r.merchantContextMiddleware.Handle())
 		{
-			merchant.POST("/products", r.handler.CreateProduct)
-			merchant.GET("/products", r.handler.GetMerchantProducts)
-			merchant.PUT("/products/:id", r.handler.UpdateProduct)
-			merchant.DELETE("/products/:id", r.handler.DeleteProduct)
+			merchant.POST("/products", middlewares.RequirePermission(rbac.ProductsWrite), r.handler.CreateProduct)
+			merchant.GET("/products", middlewares.RequirePermission(rbac.ProductsRead), r.handler.GetMerchantProducts)
+			merchant.PUT("/products/:id", middlewares.RequirePermission(rbac.ProductsWrite), r.handler.UpdateProduct)
+			merchant.DELETE("/products/:id", middlewares.RequirePermission(rbac.ProductsWrite), r.handler.DeleteProduct)
+			merchant.GET("/products/:id/price-history", middlewares.RequirePermission(rbac.ProductsRead), r.handler.GetPriceHistory)
+			merchant.GET("/markdown-rules", middlewares.RequirePermission(rbac.ProductsRead), r.handler.GetMarkdownRules)
+			merchant.PUT("/markdown-rules", middlewares.RequirePermission(rbac.ProductsWrite), r.handler.SetMarkdownRules)
 		}
 	}
 }
@@ -37,9 +43,13 @@ func (r ProductRoutes) Setup() {
 func NewProductRoutes(
 	handler *handlers.ProductHandler,
 	requestHandler lib.RequestHandler,
+	authMiddleware *middlewares.AuthMiddleware,
+	merchantContextMiddleware *middlewares.MerchantContextMiddleware,
 ) ProductRoutes {
 	return ProductRoutes{
-		handler:        handler,
-		requestHandler: requestHandler,
+		handler:                   handler,
+		requestHandler:            requestHandler,
+		authMiddleware:            authMiddleware,
+		merchantContextMiddleware: merchantContextMiddleware,
 	}
 }
@@ -9,6 +9,15 @@ var Module = fx.Options(
 	fx.Provide(NewProductRoutes),
 	fx.Provide(NewMerchantRoutes),
 	fx.Provide(NewOrderRoutes),
+	fx.Provide(NewLocationRoutes),
+	fx.Provide(NewGeocodeRoutes),
+	fx.Provide(NewProductImageRoutes),
+	fx.Provide(NewMediaRoutes),
+	fx.Provide(NewCategoryRoutes),
+	fx.Provide(NewMerchantVerificationRoutes),
+	fx.Provide(NewMerchantStaffRoutes),
+	fx.Provide(NewNotificationRoutes),
+	fx.Provide(NewAdminRoutes),
 	fx.Provide(NewRoutes),
 )
 
@@ -27,6 +36,15 @@ func NewRoutes(
 	productRoutes ProductRoutes,
 	merchantRoutes MerchantRoutes,
 	orderRoutes OrderRoutes,
+	locationRoutes LocationRoutes,
+	geocodeRoutes GeocodeRoutes,
+	productImageRoutes ProductImageRoutes,
+	mediaRoutes MediaRoutes,
+	categoryRoutes CategoryRoutes,
+	merchantVerificationRoutes MerchantVerificationRoutes,
+	merchantStaffRoutes MerchantStaffRoutes,
+	notificationRoutes NotificationRoutes,
+	adminRoutes AdminRoutes,
 ) Routes {
 	return Routes{
 		userRoutes,
@@ -34,6 +52,15 @@ func NewRoutes(
 		productRoutes,
 		merchantRoutes,
 		orderRoutes,
+		locationRoutes,
+		geocodeRoutes,
+		productImageRoutes,
+		mediaRoutes,
+		categoryRoutes,
+		merchantVerificationRoutes,
+		merchantStaffRoutes,
+		notificationRoutes,
+		adminRoutes,
 	}
 }
 
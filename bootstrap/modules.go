@@ -5,6 +5,10 @@ import (
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/middlewares"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/api/routes"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/infrastructure/database/postgres"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/infrastructure/geocoder"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/infrastructure/mailer"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/infrastructure/search"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/infrastructure/storage"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
 	handlers "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/presentation/http"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/repository"
@@ -20,6 +24,10 @@ var CommonModules = fx.Options(
 	middlewares.Module,
 	repository.Module,
 	postgres.Module,
+	geocoder.Module,
+	mailer.Module,
+	search.Module,
+	storage.Module,
 	handlers.Module,
 	fx.Provide(middlewares.NewMerchantContextMiddleware),
 )
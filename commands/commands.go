@@ -10,7 +10,9 @@ import (
 )
 
 var cmds = map[string]lib.Command{
-	"app:serve": NewServeCommand(),
+	"app:serve":      NewServeCommand(),
+	"order:expire":   NewExpireOrdersCommand(),
+	"product:expire": NewExpireProductsCommand(),
 }
 
 // GetSubCommands gives a list of sub commands
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/spf13/cobra"
+)
+
+// ExpireOrdersCommand cancels orders that were not picked up in time
+type ExpireOrdersCommand struct{}
+
+func (s *ExpireOrdersCommand) Short() string {
+	return "cancel expired orders and restore their stock"
+}
+
+func (s *ExpireOrdersCommand) Setup(cmd *cobra.Command) {}
+
+func (s *ExpireOrdersCommand) Run() lib.CommandRunner {
+	return func(
+		orderService order.Service,
+		logger lib.Logger,
+	) {
+		expired, err := orderService.ExpireOrders()
+		if err != nil {
+			logger.Error("expiring orders failed: ", err)
+		}
+		logger.Info("expired orders: ", expired)
+	}
+}
+
+func NewExpireOrdersCommand() *ExpireOrdersCommand {
+	return &ExpireOrdersCommand{}
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/spf13/cobra"
+)
+
+// ExpireProductsCommand hides expired products and applies markdown rules
+type ExpireProductsCommand struct{}
+
+func (s *ExpireProductsCommand) Short() string {
+	return "deactivate expired products and apply markdown rules"
+}
+
+func (s *ExpireProductsCommand) Setup(cmd *cobra.Command) {}
+
+func (s *ExpireProductsCommand) Run() lib.CommandRunner {
+	return func(
+		productService product.Service,
+		logger lib.Logger,
+	) {
+		expired, err := productService.ExpireProducts()
+		if err != nil {
+			logger.Error("expiring products failed: ", err)
+		}
+		logger.Info("expired products: ", expired)
+
+		markedDown, err := productService.ApplyMarkdowns()
+		if err != nil {
+			logger.Error("applying markdowns failed: ", err)
+		}
+		logger.Info("marked down products: ", markedDown)
+	}
+}
+
+func NewExpireProductsCommand() *ExpireProductsCommand {
+	return &ExpireProductsCommand{}
+}
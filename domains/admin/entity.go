@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+)
+
+// Audited admin actions
+const (
+	ActionUserDeactivate  = "user.deactivate"
+	ActionUserActivate    = "user.activate"
+	ActionOrderCancel     = "order.cancel"
+	ActionProductUnlist   = "product.unlist"
+	ActionProductRelist   = "product.relist"
+	ActionMerchantApprove = "merchant.approve"
+	ActionMerchantReject  = "merchant.reject"
+	ActionCategoryCreate  = "category.create"
+	ActionCategoryUpdate  = "category.update"
+	ActionCategoryDelete  = "category.delete"
+)
+
+// Types of the records an admin action applies to
+const (
+	TargetUser     = "user"
+	TargetMerchant = "merchant"
+	TargetOrder    = "order"
+	TargetProduct  = "product"
+	TargetCategory = "category"
+)
+
+// AuditLog records an action taken by an admin
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	AdminID    uint      `json:"admin_id" gorm:"not null"`
+	Action     string    `json:"action" gorm:"not null"`
+	TargetType string    `json:"target_type" gorm:"not null"`
+	TargetID   uint      `json:"target_id" gorm:"not null"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName gives table name of model
+func (AuditLog) TableName() string {
+	return "admin_audit_logs"
+}
+
+// AuditFilter narrows the audit log. Zero fields match everything.
+type AuditFilter struct {
+	AdminID    uint
+	Action     string
+	TargetType string
+	TargetID   uint
+}
+
+// Stats is an overview of the platform
+type Stats struct {
+	Users             int64                  `json:"users"`
+	ActiveUsers       int64                  `json:"active_users"`
+	Merchants         int64                  `json:"merchants"`
+	VerifiedMerchants int64                  `json:"verified_merchants"`
+	PendingMerchants  int64                  `json:"pending_merchants"`
+	Products          int64                  `json:"products"`
+	ListedProducts    int64                  `json:"listed_products"`
+	Orders            int64                  `json:"orders"`
+	OrdersByStatus    map[order.Status]int64 `json:"orders_by_status"`
+	OrdersLast24h     int64                  `json:"orders_last_24h"`
+	Revenue           float64                `json:"revenue"` // total of completed orders
+}
+
+// ReasonRequest carries the reason of an admin action
+type ReasonRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
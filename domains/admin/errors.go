@@ -0,0 +1,8 @@
+package admin
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by admin operations
+var (
+	ErrCannotDeactivateSelf = apperror.BadRequest("cannot_deactivate_self", "admins cannot deactivate their own account")
+)
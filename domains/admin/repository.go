@@ -0,0 +1,14 @@
+package admin
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+)
+
+// Repository defines the interface for admin data operations
+type Repository interface {
+	WithTrx(trxHandle *gorm.DB) Repository
+	CreateAuditLog(log *AuditLog) error
+	FindAuditLogs(filter *AuditFilter, page pagination.Page) ([]AuditLog, string, error)
+	GetStats() (*Stats, error)
+}
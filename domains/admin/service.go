@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+)
+
+// Service defines the interface for the admin back-office
+type Service interface {
+	ListUsers(filter *auth.UserFilter, page pagination.Page) ([]auth.User, string, error)
+	DeactivateUser(adminID uint, userID uint, req *ReasonRequest) (*auth.User, error)
+	ActivateUser(adminID uint, userID uint) (*auth.User, error)
+	ListMerchants(filter *merchant.Filter, page pagination.Page) ([]merchant.Merchant, string, error)
+	ListOrders(filter *order.Filter, page pagination.Page) ([]order.Order, string, error)
+	CancelOrder(adminID uint, orderID uint, req *ReasonRequest) (*order.Order, error)
+	UnlistProduct(adminID uint, productID uint, req *ReasonRequest) (*product.Product, error)
+	RelistProduct(adminID uint, productID uint) (*product.Product, error)
+	GetStats() (*Stats, error)
+	GetAuditLogs(filter *AuditFilter, page pagination.Page) ([]AuditLog, string, error)
+}
+
+// Auditor records admin actions in the audit log. The entry is written in
+// the transaction of the action, so an action is never left unaudited.
+type Auditor interface {
+	Record(tx *gorm.DB, adminID uint, action string, targetType string, targetID uint, reason string) error
+}
@@ -19,13 +19,16 @@ type User struct {
 
 // Session represents an authentication session
 type Session struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	UserID       uint      `json:"user_id" gorm:"not null"`
-	AccessToken  string    `json:"access_token" gorm:"unique;not null"`
-	RefreshToken string    `json:"refresh_token" gorm:"unique"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	CreatedAt    time.Time `json:"created_at"`
-	User         User      `json:"user" gorm:"foreignKey:UserID"`
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	UserID           uint       `json:"user_id" gorm:"not null"`
+	FamilyID         string     `json:"-" gorm:"index"` // shared by all sessions rotated from one login
+	AccessTokenHash  string     `json:"-" gorm:"unique;not null"` // SHA-256 hex digest, the token itself is not stored
+	RefreshTokenHash string     `json:"-" gorm:"unique"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RefreshExpiresAt time.Time  `json:"refresh_expires_at"`
+	RotatedAt        *time.Time `json:"rotated_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	User             User       `json:"user" gorm:"foreignKey:UserID"`
 }
 
 // RegisterRequest represents user registration data
@@ -42,9 +45,21 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// RefreshRequest represents a request to rotate a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 // LoginResponse represents the response after successful login
 type LoginResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	User         User   `json:"user"`
 }
+
+// UserFilter narrows a user list. Query matches the email, name or phone.
+type UserFilter struct {
+	Query    string
+	Role     string
+	IsActive *bool
+}
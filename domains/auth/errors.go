@@ -0,0 +1,17 @@
+package auth
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by authentication operations
+var (
+	ErrUserNotFound       = apperror.NotFound("user_not_found", "user not found")
+	ErrSessionNotFound    = apperror.Unauthorized("session_not_found", "session not found")
+	ErrEmailTaken         = apperror.Conflict("email_taken", "email already registered")
+	ErrInvalidCredentials = apperror.Unauthorized("invalid_credentials", "invalid credentials")
+	ErrAccountInactive    = apperror.Forbidden("account_inactive", "account is inactive")
+	ErrTokenExpired       = apperror.Unauthorized("token_expired", "token expired")
+	ErrInvalidToken       = apperror.Unauthorized("invalid_token", "invalid or expired token")
+	ErrUnauthenticated    = apperror.Unauthorized("unauthenticated", "user not authenticated")
+	ErrInvalidRefresh     = apperror.Unauthorized("invalid_refresh_token", "invalid refresh token")
+	ErrRefreshReused      = apperror.Unauthorized("refresh_token_reused", "refresh token was already used, please log in again")
+)
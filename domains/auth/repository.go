@@ -1,16 +1,27 @@
 package auth
 
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+)
+
 // Repository defines the interface for authentication data operations
 type Repository interface {
+	WithTrx(trxHandle *gorm.DB) Repository
+
 	// User operations
 	CreateUser(user *User) error
 	FindUserByEmail(email string) (*User, error)
 	FindUserByID(id uint) (*User, error)
 	UpdateUser(user *User) error
+	FindUsers(filter *UserFilter, page pagination.Page) ([]User, string, error)
 
 	// Session operations
 	CreateSession(session *Session) error
-	FindSessionByToken(token string) (*Session, error)
-	DeleteSession(token string) error
+	FindSessionByTokenHash(hash string) (*Session, error)
+	FindSessionByRefreshTokenHash(hash string) (*Session, error)
+	RotateSession(oldID uint, next *Session) error
+	DeleteSessionByTokenHash(hash string) error
+	DeleteSessionFamily(familyID string) error
 	DeleteUserSessions(userID uint) error
 }
@@ -4,7 +4,9 @@ package auth
 type Service interface {
 	Register(req *RegisterRequest) (*User, error)
 	Login(req *LoginRequest) (*LoginResponse, error)
+	Refresh(refreshToken string) (*LoginResponse, error)
 	Logout(token string) error
+	LogoutAll(userID uint) error
 	GetUserByID(id uint) (*User, error)
 	UpdateProfile(user *User) error
 	ValidateToken(token string) (*User, error)
@@ -0,0 +1,43 @@
+package category
+
+import "time"
+
+// Category is a node of the product taxonomy. Products refer to their
+// category by slug.
+type Category struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ParentID  *uint     `json:"parent_id"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null"`
+	NameVi    string    `json:"name_vi" gorm:"not null"`
+	NameEn    string    `json:"name_en" gorm:"not null"`
+	Icon      string    `json:"icon"`
+	Position  int       `json:"position" gorm:"default:0"`
+	IsActive  bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Children are the subcategories, only set when building the tree
+	Children []Category `json:"children,omitempty" gorm:"-"`
+}
+
+// CreateCategoryRequest represents request to create a category
+type CreateCategoryRequest struct {
+	ParentID *uint  `json:"parent_id"`
+	Slug     string `json:"slug" binding:"omitempty,max=100"`
+	NameVi   string `json:"name_vi" binding:"required,max=255"`
+	NameEn   string `json:"name_en" binding:"required,max=255"`
+	Icon     string `json:"icon" binding:"max=255"`
+	Position int    `json:"position"`
+}
+
+// UpdateCategoryRequest represents request to update a category. A zero
+// parent_id moves the category to the top level.
+type UpdateCategoryRequest struct {
+	ParentID *uint   `json:"parent_id"`
+	Slug     *string `json:"slug" binding:"omitempty,max=100"`
+	NameVi   *string `json:"name_vi" binding:"omitempty,max=255"`
+	NameEn   *string `json:"name_en" binding:"omitempty,max=255"`
+	Icon     *string `json:"icon" binding:"omitempty,max=255"`
+	Position *int    `json:"position"`
+	IsActive *bool   `json:"is_active"`
+}
@@ -0,0 +1,13 @@
+package category
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by category operations
+var (
+	ErrCategoryNotFound = apperror.NotFound("category_not_found", "category not found")
+	ErrSlugTaken        = apperror.Conflict("category_slug_taken", "a category with this slug already exists")
+	ErrInvalidSlug      = apperror.BadRequest("invalid_category_slug", "slug must be lowercase letters, digits and single hyphens")
+	ErrInvalidParent    = apperror.Validation("invalid_category_parent", "a category cannot be moved under itself or its subcategories")
+	ErrCategoryInUse    = apperror.Conflict("category_in_use", "category still has subcategories or products")
+	ErrInvalidCategory  = apperror.Validation("invalid_category", "category does not exist or is inactive")
+)
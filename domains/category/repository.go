@@ -0,0 +1,15 @@
+package category
+
+import "gorm.io/gorm"
+
+// Repository defines the interface for category data operations
+type Repository interface {
+	WithTrx(trxHandle *gorm.DB) Repository
+	FindAll() ([]Category, error)
+	FindByID(id uint) (*Category, error)
+	FindBySlug(slug string) (*Category, error)
+	Create(category *Category) error
+	Update(category *Category) error
+	Delete(id uint) error
+	CountProducts(slug string) (int64, error)
+}
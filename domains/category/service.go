@@ -0,0 +1,11 @@
+package category
+
+// Service defines the interface for category business logic
+type Service interface {
+	GetTree(includeInactive bool) ([]Category, error)
+	CreateCategory(adminID uint, req *CreateCategoryRequest) (*Category, error)
+	UpdateCategory(adminID uint, id uint, req *UpdateCategoryRequest) (*Category, error)
+	DeleteCategory(adminID uint, id uint) error
+	CheckAssignable(slug string) error
+	SubtreeSlugs(slug string) ([]string, error)
+}
@@ -0,0 +1,9 @@
+package geocoding
+
+// Result is a place matching a geocoding query
+type Result struct {
+	Address   string  `json:"address"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Source    string  `json:"source"`
+}
@@ -0,0 +1,9 @@
+package geocoding
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by geocoding operations
+var (
+	ErrQueryRequired = apperror.BadRequest("query_required", "query parameter q is required")
+	ErrNoMatch       = apperror.NotFound("address_not_found", "no place matches the address")
+)
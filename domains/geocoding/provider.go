@@ -0,0 +1,7 @@
+package geocoding
+
+// Provider is the adapter that turns free-text addresses into coordinates.
+// Results are ordered best match first.
+type Provider interface {
+	Geocode(query string, limit int) ([]Result, error)
+}
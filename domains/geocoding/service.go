@@ -0,0 +1,7 @@
+package geocoding
+
+// Service defines the interface for geocoding business logic
+type Service interface {
+	Search(query string) ([]Result, error)
+	FillCoordinates(address string, latitude *float64, longitude *float64)
+}
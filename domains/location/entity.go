@@ -25,3 +25,13 @@ type AddLocationRequest struct {
 	Label     string  `json:"label"`
 	IsDefault bool    `json:"is_default"`
 }
+
+// UpdateLocationRequest represents request to update a location. Omitted
+// fields are left unchanged.
+type UpdateLocationRequest struct {
+	Address   *string  `json:"address" binding:"omitempty,min=1"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Label     *string  `json:"label"`
+	IsDefault *bool    `json:"is_default"`
+}
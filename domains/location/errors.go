@@ -0,0 +1,10 @@
+package location
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by location operations
+var (
+	ErrLocationNotFound = apperror.NotFound("location_not_found", "location not found")
+	ErrNotLocationOwner = apperror.Forbidden("location_forbidden", "location does not belong to you")
+	ErrDefaultRequired  = apperror.Validation("default_location_required", "set another location as default instead")
+)
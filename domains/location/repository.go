@@ -1,10 +1,14 @@
 package location
 
+import "gorm.io/gorm"
+
 // Repository defines the interface for location data operations
 type Repository interface {
+	WithTrx(trxHandle *gorm.DB) Repository
 	Create(location *Location) error
 	FindByID(id uint) (*Location, error)
 	FindByUserID(userID uint) ([]Location, error)
+	LockUserLocations(userID uint) ([]Location, error)
 	Update(location *Location) error
 	Delete(id uint) error
 	SetDefaultLocation(userID uint, locationID uint) error
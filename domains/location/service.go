@@ -4,6 +4,7 @@ package location
 type Service interface {
 	AddLocation(userID uint, req *AddLocationRequest) (*Location, error)
 	GetUserLocations(userID uint) ([]Location, error)
+	UpdateLocation(userID uint, locationID uint, req *UpdateLocationRequest) (*Location, error)
 	SetDefaultLocation(userID uint, locationID uint) error
 	DeleteLocation(userID uint, locationID uint) error
 }
@@ -0,0 +1,13 @@
+package media
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by media operations
+var (
+	ErrFileRequired     = apperror.BadRequest("file_required", "a file is required in the file form field")
+	ErrFileTooLarge     = apperror.BadRequest("file_too_large", "the file exceeds the upload size limit")
+	ErrUnsupportedImage = apperror.BadRequest("unsupported_image", "image must be a JPEG, PNG or GIF")
+	ErrUnsupportedFile  = apperror.BadRequest("unsupported_file", "file must be a PDF, JPEG or PNG")
+	ErrInvalidKey       = apperror.BadRequest("invalid_storage_key", "invalid storage key")
+	ErrFileNotFound     = apperror.NotFound("file_not_found", "file not found")
+)
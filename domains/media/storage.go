@@ -0,0 +1,13 @@
+package media
+
+// PrivatePrefix starts the keys of files that are never served publicly,
+// such as merchant KYC documents
+const PrivatePrefix = "private/"
+
+// Storage keeps uploaded files under a key and serves them by URL
+type Storage interface {
+	Put(key string, data []byte, contentType string) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+	URL(key string) string
+}
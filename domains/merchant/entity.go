@@ -18,6 +18,16 @@ type Merchant struct {
 	IsActive    bool      `json:"is_active" gorm:"default:true"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Verification tracks the KYC review. Only approved merchants are
+	// verified and list their products publicly.
+	VerificationStatus string     `json:"verification_status" gorm:"default:unsubmitted"`
+	VerificationNote   string     `json:"verification_note,omitempty"` // reason of a rejection
+	SubmittedAt        *time.Time `json:"submitted_at,omitempty"`
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy         *uint      `json:"reviewed_by,omitempty"`
+
+	Documents []Document `json:"documents,omitempty" gorm:"foreignKey:MerchantID"`
 }
 
 // RegisterMerchantRequest represents merchant registration data
@@ -42,3 +52,10 @@ type UpdateMerchantRequest struct {
 	Longitude   float64 `json:"longitude"`
 	Description string  `json:"description"`
 }
+
+// Filter narrows a merchant list. Query matches the shop name or phone.
+type Filter struct {
+	Query              string
+	VerificationStatus string
+	IsActive           *bool
+}
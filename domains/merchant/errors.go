@@ -0,0 +1,26 @@
+package merchant
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by merchant operations
+var (
+	ErrMerchantNotFound = apperror.NotFound("merchant_not_found", "merchant not found")
+
+	ErrDocumentNotFound     = apperror.NotFound("document_not_found", "document not found")
+	ErrInvalidDocumentType  = apperror.BadRequest("invalid_document_type", "type must be business_license, food_safety_certificate or other")
+	ErrTooManyDocuments     = apperror.Validation("too_many_documents", "too many documents uploaded")
+	ErrDocumentsMissing     = apperror.Validation("documents_missing", "a business licence and a food-safety certificate are required")
+	ErrVerificationLocked   = apperror.Conflict("verification_locked", "documents cannot change while the review is pending or after approval")
+	ErrNotPendingReview     = apperror.Conflict("not_pending_review", "merchant is not pending review")
+	ErrVerificationChanged  = apperror.Conflict("verification_changed", "verification status was changed concurrently")
+	ErrCannotSubmitApproved = apperror.Conflict("already_verified", "merchant is already verified")
+
+	ErrMemberNotFound     = apperror.NotFound("member_not_found", "member not found")
+	ErrInvitationNotFound = apperror.NotFound("invitation_not_found", "invitation not found")
+	ErrInvitationToken    = apperror.Forbidden("invalid_invitation_token", "the invitation token is invalid")
+	ErrInvitationExpired  = apperror.Conflict("invitation_expired", "the invitation has expired, ask the shop to invite you again")
+	ErrAlreadyInvited     = apperror.Conflict("already_invited", "this email is already a member or invited")
+	ErrAlreadyMember      = apperror.Conflict("already_member", "user already works for a shop")
+	ErrCannotChangeOwner  = apperror.Conflict("cannot_change_owner", "the owner of the shop cannot be changed or revoked")
+	ErrMemberChanged      = apperror.Conflict("member_changed", "membership was changed concurrently")
+)
@@ -0,0 +1,60 @@
+package merchant
+
+import "time"
+
+// Membership statuses. An invitation waits for the invited user to accept
+// or decline it; revoked members lose access to the shop at once.
+const (
+	MemberInvited  = "invited"
+	MemberActive   = "active"
+	MemberDeclined = "declined"
+	MemberRevoked  = "revoked"
+)
+
+// InvitationTTL is how long an invitation can be answered
+const InvitationTTL = 7 * 24 * time.Hour
+
+// Member links a user to the shop they work for with their role in it
+// (owner, manager or cashier). Invitations are addressed by email, so the
+// user is only known once they accept. The invitation email carries a
+// one-time token proving the user reads that mailbox; only its hash is kept.
+type Member struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	MerchantID uint       `json:"merchant_id" gorm:"not null"`
+	UserID     *uint      `json:"user_id,omitempty"`
+	Email      string     `json:"email" gorm:"not null"`
+	Role       string     `json:"role" gorm:"not null"`
+	Status     string     `json:"status" gorm:"not null"`
+	InvitedBy  *uint      `json:"invited_by,omitempty"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy  *uint      `json:"revoked_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	InvitationTokenHash string     `json:"-"`
+	InvitationExpiresAt *time.Time `json:"invitation_expires_at,omitempty"`
+
+	Merchant *Merchant `json:"merchant,omitempty" gorm:"foreignKey:MerchantID"`
+}
+
+// TableName gives table name of model
+func (Member) TableName() string {
+	return "merchant_members"
+}
+
+// InviteMemberRequest represents request to invite a user to the shop
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=manager cashier"`
+}
+
+// AnswerInvitationRequest carries the token of the invitation email
+type AnswerInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// UpdateMemberRequest represents request to change the role of a member
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=manager cashier"`
+}
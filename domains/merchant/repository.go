@@ -1,11 +1,33 @@
 package merchant
 
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+)
+
 // Repository defines the interface for merchant data operations
 type Repository interface {
+	WithTrx(trxHandle *gorm.DB) Repository
+
 	Create(merchant *Merchant) error
 	FindByID(id uint) (*Merchant, error)
+	FindByIDs(ids []uint) ([]Merchant, error)
 	FindByUserID(userID uint) (*Merchant, error)
 	Update(merchant *Merchant) error
 	Delete(id uint) error
 	FindAll() ([]Merchant, error)
+	FindMerchants(filter *Filter, page pagination.Page) ([]Merchant, string, error)
+	FindPendingReview() ([]Merchant, error)
+	UpdateVerification(merchant *Merchant, from string) error
+	CreateDocument(document *Document) error
+	FindDocuments(merchantID uint) ([]Document, error)
+	FindDocument(merchantID uint, documentID uint) (*Document, error)
+	CreateMember(member *Member) error
+	FindMember(merchantID uint, id uint) (*Member, error)
+	FindMembers(merchantID uint) ([]Member, error)
+	FindOpenMemberByEmail(merchantID uint, email string) (*Member, error)
+	FindActiveMemberByUserID(userID uint) (*Member, error)
+	FindInvitations(email string) ([]Member, error)
+	FindInvitation(id uint, email string) (*Member, error)
+	UpdateMember(member *Member, from string) error
 }
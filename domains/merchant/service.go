@@ -7,3 +7,26 @@ type Service interface {
 	GetMerchantByUserID(userID uint) (*Merchant, error)
 	UpdateMerchant(id uint, req *UpdateMerchantRequest) error
 }
+
+// StaffService defines the interface for managing the members of a shop
+type StaffService interface {
+	GetMembership(userID uint) (*Member, error)
+	GetMembers(merchantID uint) ([]Member, error)
+	InviteMember(merchantID uint, ownerID uint, req *InviteMemberRequest) (*Member, error)
+	UpdateMember(merchantID uint, memberID uint, req *UpdateMemberRequest) (*Member, error)
+	RevokeMember(merchantID uint, memberID uint, ownerID uint) error
+	GetInvitations(userID uint) ([]Member, error)
+	AcceptInvitation(userID uint, invitationID uint, token string) (*Member, error)
+	DeclineInvitation(userID uint, invitationID uint, token string) error
+}
+
+// VerificationService defines the interface for the merchant KYC review
+type VerificationService interface {
+	UploadDocument(merchantID uint, upload *DocumentUpload) (*Document, error)
+	GetVerification(merchantID uint) (*Merchant, error)
+	SubmitForReview(merchantID uint) (*Merchant, error)
+	GetPendingMerchants() ([]Merchant, error)
+	GetDocumentFile(merchantID uint, documentID uint) (*Document, []byte, error)
+	Approve(merchantID uint, adminID uint) (*Merchant, error)
+	Reject(merchantID uint, adminID uint, req *RejectMerchantRequest) (*Merchant, error)
+}
@@ -0,0 +1,57 @@
+package merchant
+
+import "time"
+
+// Verification statuses of a merchant
+const (
+	VerificationUnsubmitted = "unsubmitted"
+	VerificationPending     = "pending"
+	VerificationApproved    = "approved"
+	VerificationRejected    = "rejected"
+)
+
+// KYC document types
+const (
+	DocumentBusinessLicense = "business_license"
+	DocumentFoodSafety      = "food_safety_certificate"
+	DocumentOther           = "other"
+)
+
+// Document upload limits
+const (
+	MaxDocumentBytes        = 10 << 20
+	MaxDocumentsPerMerchant = 20
+)
+
+// RequiredDocuments must all be uploaded before submitting for review
+var RequiredDocuments = []string{DocumentBusinessLicense, DocumentFoodSafety}
+
+// Document is a KYC document uploaded by a merchant. The file is kept in
+// private storage and only served to admins.
+type Document struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	MerchantID  uint      `json:"merchant_id" gorm:"not null"`
+	Type        string    `json:"type" gorm:"not null"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int       `json:"size"`
+	StorageKey  string    `json:"-" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName gives table name of model
+func (Document) TableName() string {
+	return "merchant_documents"
+}
+
+// DocumentUpload is an uploaded KYC document file
+type DocumentUpload struct {
+	Type     string
+	FileName string
+	Data     []byte
+}
+
+// RejectMerchantRequest represents request to reject a merchant
+type RejectMerchantRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
@@ -0,0 +1,22 @@
+package notification
+
+import "time"
+
+// Notification types
+const (
+	TypeMerchantApproved = "merchant_approved"
+	TypeMerchantRejected = "merchant_rejected"
+	TypeOrderCancelled   = "order_cancelled"
+	TypeStaffInvitation  = "staff_invitation"
+)
+
+// Notification is an in-app message to a user
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null"`
+	Type      string     `json:"type" gorm:"not null"`
+	Title     string     `json:"title" gorm:"not null"`
+	Body      string     `json:"body"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
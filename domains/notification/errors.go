@@ -0,0 +1,8 @@
+package notification
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by notification operations
+var (
+	ErrNotificationNotFound = apperror.NotFound("notification_not_found", "notification not found")
+)
@@ -0,0 +1,10 @@
+package notification
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+
+// Repository defines the interface for notification data operations
+type Repository interface {
+	Create(notification *Notification) error
+	FindByUserID(userID uint, page pagination.Page) ([]Notification, string, error)
+	MarkRead(userID uint, id uint) (bool, error)
+}
@@ -0,0 +1,10 @@
+package notification
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+
+// Service defines the interface for notification business logic
+type Service interface {
+	Notify(userID uint, notificationType string, title string, body string) error
+	GetUserNotifications(userID uint, page pagination.Page) ([]Notification, string, error)
+	MarkRead(userID uint, id uint) error
+}
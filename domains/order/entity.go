@@ -2,6 +2,9 @@ package order
 
 import (
 	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
 )
 
 // Order represents a customer order
@@ -11,12 +14,17 @@ type Order struct {
 	MerchantID      uint        `json:"merchant_id" gorm:"not null"`
 	OrderCode       string      `json:"order_code" gorm:"unique;not null"`
 	TotalAmount     float64     `json:"total_amount" gorm:"not null"`
-	Status          string      `json:"status" gorm:"default:'pending'"` // pending, confirmed, ready, completed, cancelled
+	Status          Status      `json:"status" gorm:"default:'pending'"` // pending, confirmed, ready, completed, cancelled
 	PaymentMethod   string      `json:"payment_method" gorm:"default:'COD'"`
 	PaymentStatus   string      `json:"payment_status" gorm:"default:'unpaid'"` // unpaid, paid
 	DeliveryAddress string      `json:"delivery_address"`
 	PickupTime      time.Time   `json:"pickup_time"`
+	ConfirmedAt     *time.Time  `json:"confirmed_at"`
+	ReadyAt         *time.Time  `json:"ready_at"`
 	CompletedAt     *time.Time  `json:"completed_at"`
+	CancelledAt     *time.Time  `json:"cancelled_at"`
+	CancelReason    string      `json:"cancel_reason"`
+	StockReleased   bool        `json:"-" gorm:"default:false"`
 	Notes           string      `json:"notes"`
 	CreatedAt       time.Time   `json:"created_at"`
 	UpdatedAt       time.Time   `json:"updated_at"`
@@ -34,6 +42,23 @@ type OrderItem struct {
 	ProductName string  `json:"product_name"`
 }
 
+// StatusHistory records a single status transition of an order
+type StatusHistory struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	OrderID    uint      `json:"order_id" gorm:"not null"`
+	FromStatus Status    `json:"from_status" gorm:"not null"`
+	ToStatus   Status    `json:"to_status" gorm:"not null"`
+	Actor      string    `json:"actor" gorm:"not null"` // customer, merchant, system, admin
+	ActorID    *uint     `json:"actor_id,omitempty"`    // user who made the change, a staff member for merchant actions
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName gives table name of model
+func (StatusHistory) TableName() string {
+	return "order_status_history"
+}
+
 // Cart represents a shopping cart
 type Cart struct {
 	ID        uint       `json:"id" gorm:"primaryKey"`
@@ -54,22 +79,80 @@ type CartItem struct {
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// Reasons a cart item can no longer be ordered
+const (
+	ItemIssueUnavailable       = "unavailable"
+	ItemIssueInactive          = "inactive"
+	ItemIssueExpired           = "expired"
+	ItemIssueOutOfStock        = "out_of_stock"
+	ItemIssueInsufficientStock = "insufficient_stock"
+)
+
+// CartView represents the cart grouped by shop with live product data
+type CartView struct {
+	CartID    uint       `json:"cart_id"`
+	Shops     []CartShop `json:"shops"`
+	Total     float64    `json:"total"`
+	ItemCount int        `json:"item_count"`
+}
+
+// CartShop represents the cart items of a single shop
+type CartShop struct {
+	Merchant *merchant.Merchant `json:"merchant"`
+	Items    []CartViewItem     `json:"items"`
+	Subtotal float64            `json:"subtotal"` // available items only
+}
+
+// CartViewItem represents a cart item with the current state of its product
+type CartViewItem struct {
+	ID         uint            `json:"id"`
+	ProductID  uint            `json:"product_id"`
+	Name       string          `json:"name"`
+	Quantity   int             `json:"quantity"`
+	OrigPrice  float64         `json:"orig_price"`
+	SalePrice  float64         `json:"sale_price"`
+	Discount   float64         `json:"discount"`
+	Images     []product.Image `json:"images"`
+	ExpiryDate time.Time       `json:"expiry_date"`
+	Stock      int             `json:"stock"`
+	Subtotal   float64         `json:"subtotal"`
+	Available  bool            `json:"available"`
+	Issue      string          `json:"issue,omitempty"` // inactive, expired, out_of_stock, insufficient_stock, unavailable
+}
+
 // CreateOrderRequest represents request to create an order
 type CreateOrderRequest struct {
-	MerchantID      uint   `json:"merchant_id" binding:"required"`
+	MerchantID      uint               `json:"merchant_id" binding:"required"`
+	DeliveryAddress string             `json:"delivery_address" binding:"required"`
+	PaymentMethod   string             `json:"payment_method" binding:"required"`
+	Notes           string             `json:"notes"`
+	Items           []OrderItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// OrderItemRequest represents a product and quantity to order
+type OrderItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+}
+
+// CheckoutRequest represents request to place orders from the cart
+type CheckoutRequest struct {
 	DeliveryAddress string `json:"delivery_address" binding:"required"`
 	PaymentMethod   string `json:"payment_method" binding:"required"`
 	Notes           string `json:"notes"`
-	Items           []struct {
-		ProductID uint `json:"product_id" binding:"required"`
-		Quantity  int  `json:"quantity" binding:"required,gt=0"`
-	} `json:"items" binding:"required,min=1"`
+	ItemIDs         []uint `json:"item_ids"` // cart items to check out, all when empty
+}
+
+// CheckoutResponse represents the orders placed from the cart, one per merchant
+type CheckoutResponse struct {
+	OrderCodes []string `json:"order_codes"`
+	Orders     []Order  `json:"orders"`
 }
 
 // AddToCartRequest represents request to add item to cart
 type AddToCartRequest struct {
 	ProductID  uint `json:"product_id" binding:"required"`
-	MerchantID uint `json:"merchant_id" binding:"required"`
+	MerchantID uint `json:"merchant_id"` // optional, taken from the product
 	Quantity   int  `json:"quantity" binding:"required,gt=0"`
 }
 
@@ -78,7 +161,27 @@ type UpdateCartItemRequest struct {
 	Quantity int `json:"quantity" binding:"required,gte=0"`
 }
 
-// RedeemOrderRequest represents request to redeem/complete an order
+// CancelOrderRequest represents request to cancel or reject an order
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RedeemOrderRequest represents request to redeem/complete an order with the
+// token scanned from the customer's pickup QR code
 type RedeemOrderRequest struct {
-	OrderCode string `json:"order_code" binding:"required"`
+	Token string `json:"token" binding:"required"`
+}
+
+// RedeemToken is a signed, short-lived token completing an order at pickup
+type RedeemToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Filter narrows an order list. Query matches the order code.
+type Filter struct {
+	Query      string
+	Status     Status
+	UserID     uint
+	MerchantID uint
 }
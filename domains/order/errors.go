@@ -0,0 +1,26 @@
+package order
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by order and cart operations
+var (
+	ErrOrderNotFound     = apperror.NotFound("order_not_found", "order not found")
+	ErrNotOrderOwner     = apperror.Forbidden("order_forbidden", "order does not belong to you")
+	ErrInvalidTransition = apperror.Conflict("order_invalid_transition", "order cannot change to this status")
+	ErrOrderChanged      = apperror.Conflict("order_changed", "order status changed, please retry")
+	ErrNotRedeemable     = apperror.Conflict("order_not_redeemable", "order cannot be redeemed in current status")
+	ErrNotCancellable    = apperror.Conflict("order_not_cancellable", "order can only be cancelled while pending")
+	ErrPickupExpired     = apperror.Conflict("order_pickup_expired", "order pickup time has expired")
+	ErrInvalidRedeemCode = apperror.BadRequest("invalid_redeem_code", "pickup code is invalid")
+	ErrRedeemCodeExpired = apperror.Conflict("redeem_code_expired", "pickup code has expired, ask the customer to refresh it")
+	ErrAlreadyRedeemed   = apperror.Conflict("order_already_redeemed", "order was already redeemed")
+	ErrMixedMerchants    = apperror.Validation("order_mixed_merchants", "all products must be from the same merchant")
+	ErrCartEmpty         = apperror.Validation("cart_empty", "cart is empty")
+	ErrCartItemNotFound  = apperror.NotFound("cart_item_not_found", "cart item not found")
+	ErrDuplicateCartItem = apperror.BadRequest("cart_item_duplicate", "cart item is selected more than once")
+	ErrNotCartOwner      = apperror.Forbidden("cart_item_forbidden", "cart item does not belong to you")
+	ErrProductInactive   = apperror.Validation("product_inactive", "product is no longer available")
+	ErrProductExpired    = apperror.Validation("product_expired", "product has expired")
+	ErrMerchantMismatch  = apperror.Validation("merchant_mismatch", "product does not belong to the given merchant")
+	ErrInsufficientStock = apperror.InsufficientStock("insufficient_stock", "insufficient stock")
+)
@@ -1,14 +1,27 @@
 package order
 
+import (
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+)
+
 // Repository defines the interface for order data operations
 type Repository interface {
+	WithTrx(trxHandle *gorm.DB) Repository
+
 	// Order operations
 	CreateOrder(order *Order) error
 	FindOrderByID(id uint) (*Order, error)
 	FindOrderByCode(code string) (*Order, error)
-	FindOrdersByUserID(userID uint) ([]Order, error)
-	FindOrdersByMerchantID(merchantID uint) ([]Order, error)
+	FindOrdersByUserID(userID uint, page pagination.Page) ([]Order, string, error)
+	FindOrdersByMerchantID(merchantID uint, page pagination.Page) ([]Order, string, error)
+	FindOrders(filter *Filter, page pagination.Page) ([]Order, string, error)
 	UpdateOrder(order *Order) error
+	UpdateOrderStatus(order *Order, from Status, history *StatusHistory) error
+	FindExpiredOrders(pickupBefore time.Time) ([]Order, error)
+	MarkStockReleased(orderID uint) (bool, error)
 
 	// Cart operations
 	CreateCart(cart *Cart) error
@@ -16,6 +29,7 @@ type Repository interface {
 	AddCartItem(item *CartItem) error
 	UpdateCartItem(item *CartItem) error
 	RemoveCartItem(id uint) error
+	RemoveCartItems(cartID uint, ids []uint) error
 	ClearCart(cartID uint) error
 	FindCartItemsByCartID(cartID uint) ([]CartItem, error)
 	FindCartItemByID(id uint) (*CartItem, error)
@@ -1,19 +1,29 @@
 package order
 
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+
 // Service defines the interface for order business logic
 type Service interface {
 	// Order operations
 	CreateOrder(userID uint, req *CreateOrderRequest) (*Order, error)
 	GetOrderByID(id uint) (*Order, error)
 	GetOrderByCode(code string) (*Order, error)
-	GetUserOrders(userID uint) ([]Order, error)
-	GetMerchantOrders(merchantID uint) ([]Order, error)
-	RedeemOrder(merchantID uint, orderCode string) error
+	GetUserOrders(userID uint, page pagination.Page) ([]Order, string, error)
+	GetMerchantOrders(merchantID uint, page pagination.Page) ([]Order, string, error)
+	GetRedeemToken(userID uint, orderID uint) (*RedeemToken, error)
+	RedeemOrder(merchantID uint, staffID uint, token string) (*Order, error)
+	ConfirmOrder(merchantID uint, staffID uint, orderID uint) error
+	MarkOrderReady(merchantID uint, staffID uint, orderID uint) error
+	RejectOrder(merchantID uint, staffID uint, orderID uint, reason string) error
+	CancelOrder(userID uint, orderID uint, reason string) error
+	ForceCancelOrder(orderID uint, adminID uint, reason string) (*Order, error)
+	ExpireOrders() (int, error)
 
 	// Cart operations
 	AddToCart(userID uint, req *AddToCartRequest) error
-	GetCart(userID uint) (*Cart, error)
+	GetCart(userID uint) (*CartView, error)
 	UpdateCartItem(userID uint, itemID uint, quantity int) error
 	RemoveCartItem(userID uint, itemID uint) error
 	ClearCart(userID uint) error
+	Checkout(userID uint, req *CheckoutRequest) (*CheckoutResponse, error)
 }
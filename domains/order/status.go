@@ -0,0 +1,81 @@
+package order
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status represents the lifecycle state of an order
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusConfirmed Status = "confirmed"
+	StatusReady     Status = "ready"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+)
+
+// PickupWindow is how long after the pickup time an order can still be redeemed
+const PickupWindow = 24 * time.Hour
+
+// RedeemTokenTTL is how long a pickup QR code stays valid. The customer
+// opens it at the counter, so a screenshot shared earlier is useless.
+const RedeemTokenTTL = 5 * time.Minute
+
+// Actors that can move an order between statuses
+const (
+	ActorCustomer = "customer"
+	ActorMerchant = "merchant"
+	ActorSystem   = "system"
+	ActorAdmin    = "admin"
+)
+
+// transitions lists the statuses an order may move to from each status.
+// Completed and cancelled orders are final.
+var transitions = map[Status][]Status{
+	StatusPending:   {StatusConfirmed, StatusCompleted, StatusCancelled},
+	StatusConfirmed: {StatusReady, StatusCompleted, StatusCancelled},
+	StatusReady:     {StatusCompleted, StatusCancelled},
+}
+
+// CanTransitionTo reports whether an order in status s may move to next
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether no further transitions are allowed from s
+func (s Status) IsFinal() bool {
+	return len(transitions[s]) == 0
+}
+
+// TransitionTo moves the order to the next status and stamps the matching timestamp
+func (o *Order) TransitionTo(next Status, at time.Time) error {
+	if !o.Status.CanTransitionTo(next) {
+		return ErrInvalidTransition.WithMessage(fmt.Sprintf("order cannot move from %s to %s", o.Status, next))
+	}
+
+	o.Status = next
+	switch next {
+	case StatusConfirmed:
+		o.ConfirmedAt = &at
+	case StatusReady:
+		o.ReadyAt = &at
+	case StatusCompleted:
+		o.CompletedAt = &at
+	case StatusCancelled:
+		o.CancelledAt = &at
+	}
+
+	return nil
+}
+
+// IsPickupExpired reports whether the pickup window of the order has passed
+func (o *Order) IsPickupExpired(now time.Time) bool {
+	return now.After(o.PickupTime.Add(PickupWindow))
+}
@@ -2,6 +2,8 @@ package product
 
 import (
 	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
 )
 
 // Product represents a product/smart bag in the system
@@ -10,28 +12,134 @@ type Product struct {
 	MerchantID  uint      `json:"merchant_id" gorm:"not null"`
 	Name        string    `json:"name" gorm:"not null"`
 	Description string    `json:"description"`
-	Category    string    `json:"category" gorm:"not null"` // vegetables, fruits, meat, bakery, etc.
+	Category    string    `json:"category" gorm:"not null"` // category slug
 	OrigPrice   float64   `json:"orig_price" gorm:"not null"`
 	SalePrice   float64   `json:"sale_price" gorm:"not null"`
 	Discount    float64   `json:"discount"` // percentage
 	Stock       int       `json:"stock" gorm:"default:0"`
-	Images      string    `json:"images"` // comma-separated URLs
 	ExpiryDate  time.Time `json:"expiry_date"`
 	IsActive    bool      `json:"is_active" gorm:"default:true"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// UnlistedAt is set when an admin takes the product down. Unlisted
+	// products stay hidden whatever the merchant sets IsActive to.
+	UnlistedAt   *time.Time `json:"unlisted_at,omitempty"`
+	UnlistReason string     `json:"unlist_reason,omitempty"`
+
+	Images []Image `json:"images" gorm:"foreignKey:ProductID"`
+
+	// DistanceKm is the distance to the searched point, only set by proximity search
+	DistanceKm *float64 `json:"distance_km,omitempty" gorm:"column:distance_km;->;-:migration"`
+}
+
+// IsUnlisted reports whether an admin took the product down
+func (p *Product) IsUnlisted() bool {
+	return p.UnlistedAt != nil
+}
+
+// IsExpired reports whether the product is past its expiry date
+func (p *Product) IsExpired(now time.Time) bool {
+	return !p.ExpiryDate.IsZero() && now.After(p.ExpiryDate)
+}
+
+// Sort orders for product search
+const (
+	SortNewest    = "newest"
+	SortDistance  = "distance"
+	SortRelevance = "relevance"
+	SortDiscount  = "discount"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortExpiry    = "expiry"
+)
+
+// Search radius bounds in kilometres
+const (
+	DefaultRadiusKm = 5.0
+	MaxRadiusKm     = 50.0
+)
+
+// GeoPoint is a WGS84 coordinate
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Valid reports whether the point is a valid coordinate
+func (p GeoPoint) Valid() bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
 }
 
 // SearchFilter represents search and filter criteria
 type SearchFilter struct {
-	Keyword    string
+	Keyword string
+	// Category is a category slug. The service expands it into Categories,
+	// adding the slugs of its subcategories.
 	Category   string
+	Categories []string
 	MinPrice   float64
 	MaxPrice   float64
 	MerchantID uint
-	Location   string
-	Limit      int
-	Offset     int
+	// MinDiscount is the lowest discount percentage to include
+	MinDiscount float64
+	// ExpiringWithin keeps products expiring between now and now+ExpiringWithin
+	ExpiringWithin time.Duration
+	InStock        bool
+	// Near restricts results to shops within RadiusKm of the point. It can
+	// also be resolved from a saved location of UserID via LocationID.
+	Near       *GeoPoint
+	RadiusKm   float64
+	LocationID uint
+	UserID     uint
+	SortBy     string
+	Page       pagination.Page
+	// ProductIDs restricts results to keyword hits, best match first. It is
+	// set by the service from the search index.
+	ProductIDs []uint
+}
+
+// PriceRange is a sale price bucket. A zero Max means no upper bound.
+type PriceRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max,omitempty"`
+}
+
+// PriceBuckets are the sale price ranges counted in search facets, in VND
+var PriceBuckets = []PriceRange{
+	{Min: 0, Max: 20000},
+	{Min: 20000, Max: 50000},
+	{Min: 50000, Max: 100000},
+	{Min: 100000, Max: 200000},
+	{Min: 200000},
+}
+
+// CategoryFacet counts matching products in a category
+type CategoryFacet struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// PriceFacet counts matching products in a price range
+type PriceFacet struct {
+	PriceRange
+	Count int64 `json:"count"`
+}
+
+// Facets summarize search results for building filters. Each facet ignores
+// its own filter, so the other choices stay visible.
+type Facets struct {
+	Categories  []CategoryFacet `json:"categories"`
+	PriceRanges []PriceFacet    `json:"price_ranges"`
+}
+
+// SearchResult is a page of products. The total and facet counts are only
+// computed for the first page.
+type SearchResult struct {
+	Products   []Product
+	NextCursor string
+	Total      *int64
+	Facets     *Facets
 }
 
 // CreateProductRequest represents request to create a product
@@ -42,7 +150,6 @@ type CreateProductRequest struct {
 	OrigPrice   float64   `json:"orig_price" binding:"required,gt=0"`
 	SalePrice   float64   `json:"sale_price" binding:"required,gt=0"`
 	Stock       int       `json:"stock" binding:"required,gte=0"`
-	Images      string    `json:"images"`
 	ExpiryDate  time.Time `json:"expiry_date" binding:"required"`
 }
 
@@ -54,7 +161,6 @@ type UpdateProductRequest struct {
 	OrigPrice   float64   `json:"orig_price"`
 	SalePrice   float64   `json:"sale_price"`
 	Stock       int       `json:"stock"`
-	Images      string    `json:"images"`
 	ExpiryDate  time.Time `json:"expiry_date"`
 	IsActive    bool      `json:"is_active"`
 }
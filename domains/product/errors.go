@@ -0,0 +1,24 @@
+package product
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by product operations
+var (
+	ErrProductNotFound = apperror.NotFound("product_not_found", "product not found")
+	ErrNotProductOwner = apperror.Forbidden("product_forbidden", "product does not belong to your shop")
+
+	ErrInvalidCoordinates = apperror.BadRequest("invalid_coordinates", "lat must be within [-90, 90] and lng within [-180, 180]")
+	ErrInvalidRadius      = apperror.BadRequest("invalid_radius", "radius must be positive and at most 50 km")
+	ErrLocationRequired   = apperror.BadRequest("location_required", "sorting by distance requires lat/lng or location_id")
+	ErrInvalidSort        = apperror.BadRequest("invalid_sort", "unsupported sort order")
+	ErrInvalidFilter      = apperror.BadRequest("invalid_filter", "invalid search filter")
+
+	ErrImageNotFound     = apperror.NotFound("image_not_found", "image not found")
+	ErrTooManyImages     = apperror.Validation("too_many_images", "a product can have at most 10 images")
+	ErrInvalidImageOrder = apperror.BadRequest("invalid_image_order", "image_ids must list every image of the product exactly once")
+
+	ErrAlreadyUnlisted = apperror.Conflict("product_already_unlisted", "product is already unlisted")
+	ErrNotUnlisted     = apperror.Conflict("product_not_unlisted", "product is not unlisted")
+
+	ErrInvalidMarkdownRule = apperror.Validation("invalid_markdown_rule", "each markdown rule needs a distinct hours_before_expiry")
+)
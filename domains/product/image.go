@@ -0,0 +1,47 @@
+package product
+
+import "time"
+
+// Image upload limits
+const (
+	MaxImagesPerProduct = 10
+	MaxImageBytes       = 5 << 20
+	ThumbnailSize       = 320
+)
+
+// Image is a picture of a product. Position orders the images of a
+// product, the first one being the cover.
+type Image struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ProductID    uint      `json:"product_id" gorm:"not null"`
+	URL          string    `json:"url" gorm:"not null"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	StorageKey   string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	Position     int       `json:"position" gorm:"not null;default:0"`
+	AltText      string    `json:"alt_text"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName gives table name of model
+func (Image) TableName() string {
+	return "product_images"
+}
+
+// ImageUpload is an uploaded image file
+type ImageUpload struct {
+	Data    []byte
+	AltText string
+}
+
+// UpdateImageRequest represents request to update an image
+type UpdateImageRequest struct {
+	AltText string `json:"alt_text" binding:"max=255"`
+}
+
+// ReorderImagesRequest lists all image IDs of a product in their new order
+type ReorderImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required"`
+}
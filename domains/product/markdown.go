@@ -0,0 +1,33 @@
+package product
+
+import "time"
+
+// MaxMarkdownRules caps the markdown rules of a merchant
+const MaxMarkdownRules = 10
+
+// MarkdownRule discounts a merchant's products once they are within
+// HoursBeforeExpiry of their expiry date, e.g. 30% off at T-6h
+type MarkdownRule struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	MerchantID        uint      `json:"merchant_id" gorm:"not null"`
+	HoursBeforeExpiry int       `json:"hours_before_expiry" gorm:"not null"`
+	DiscountPercent   float64   `json:"discount_percent" gorm:"not null"` // off the original price
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// Applies reports whether the rule is due for a product expiring at expiry
+func (r *MarkdownRule) Applies(expiry time.Time, now time.Time) bool {
+	return !now.Before(expiry.Add(-time.Duration(r.HoursBeforeExpiry) * time.Hour))
+}
+
+// MarkdownRuleInput is a single rule of a SetMarkdownRulesRequest
+type MarkdownRuleInput struct {
+	HoursBeforeExpiry int     `json:"hours_before_expiry" binding:"required,gt=0,lte=168"`
+	DiscountPercent   float64 `json:"discount_percent" binding:"required,gt=0,lt=100"`
+}
+
+// SetMarkdownRulesRequest replaces all markdown rules of a merchant. An
+// empty list turns markdowns off.
+type SetMarkdownRulesRequest struct {
+	Rules []MarkdownRuleInput `json:"rules" binding:"max=10,dive"`
+}
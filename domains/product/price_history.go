@@ -0,0 +1,33 @@
+package product
+
+import "time"
+
+// Actors and reasons of a price change
+const (
+	PriceActorMerchant = "merchant"
+	PriceActorSystem   = "system"
+
+	PriceReasonCreated  = "created"
+	PriceReasonManual   = "manual"
+	PriceReasonMarkdown = "markdown"
+)
+
+// PriceChange records a single change of a product's original or sale price
+type PriceChange struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ProductID    uint      `json:"product_id" gorm:"not null"`
+	MerchantID   uint      `json:"merchant_id" gorm:"not null"`
+	OldOrigPrice float64   `json:"old_orig_price" gorm:"not null"`
+	NewOrigPrice float64   `json:"new_orig_price" gorm:"not null"`
+	OldSalePrice float64   `json:"old_sale_price" gorm:"not null"`
+	NewSalePrice float64   `json:"new_sale_price" gorm:"not null"`
+	Actor        string    `json:"actor" gorm:"not null"`  // merchant, system
+	ActorID      *uint     `json:"actor_id,omitempty"`     // user who made a manual change, the owner or a staff member
+	Reason       string    `json:"reason" gorm:"not null"` // created, manual, markdown
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName gives table name of model
+func (PriceChange) TableName() string {
+	return "product_price_history"
+}
@@ -1,12 +1,44 @@
 package product
 
+import (
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+)
+
 // Repository defines the interface for product data operations
 type Repository interface {
+	WithTrx(trxHandle *gorm.DB) Repository
 	Create(product *Product) error
 	FindByID(id uint) (*Product, error)
-	FindAll(filter *SearchFilter) ([]Product, int64, error)
+	FindListedByID(id uint) (*Product, error)
+	LockByID(id uint) (*Product, error)
+	FindByIDs(ids []uint) ([]Product, error)
+	FindAll(filter *SearchFilter) ([]Product, string, error)
+	Count(filter *SearchFilter) (int64, error)
+	FindActive() ([]Product, error)
+	Facets(filter *SearchFilter) (*Facets, error)
 	Update(product *Product) error
 	Delete(id uint) error
+	Unlist(id uint, reason string, at time.Time) (bool, error)
+	Relist(id uint) (bool, error)
 	UpdateStock(id uint, quantity int) error
-	FindByMerchantID(merchantID uint) ([]Product, error)
+	IncrementStock(id uint, quantity int) error
+	ReserveStock(id uint, quantity int) (bool, error)
+	FindByMerchantID(merchantID uint, page pagination.Page) ([]Product, string, error)
+	FindExpiringBefore(before time.Time) ([]Product, error)
+	DeactivateExpired(id uint, now time.Time) (bool, error)
+	UpdateSalePrice(prod *Product, from float64, change *PriceChange) (bool, error)
+	RecordPriceChange(change *PriceChange) error
+	FindPriceHistory(productID uint, page pagination.Page) ([]PriceChange, string, error)
+	FindImages(productID uint) ([]Image, error)
+	FindImage(productID uint, imageID uint) (*Image, error)
+	CreateImage(image *Image) error
+	UpdateImage(image *Image) error
+	DeleteImage(imageID uint) error
+	SetImagePosition(imageID uint, position int) error
+	FindMarkdownRules(merchantID uint) ([]MarkdownRule, error)
+	FindAllMarkdownRules() ([]MarkdownRule, error)
+	ReplaceMarkdownRules(merchantID uint, rules []MarkdownRule) error
 }
@@ -0,0 +1,15 @@
+package product
+
+// SearchHit is a product matching a keyword search with its relevance score
+type SearchHit struct {
+	ProductID uint
+	Score     float64
+}
+
+// SearchIndex is the full-text backend behind keyword search. It is kept in
+// sync with product writes and returns hits ordered by relevance.
+type SearchIndex interface {
+	Index(product *Product) error
+	Remove(id uint) error
+	Search(query string, limit int) ([]SearchHit, error)
+}
@@ -1,12 +1,29 @@
 package product
 
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+
 // Service defines the interface for product business logic
 type Service interface {
-	CreateProduct(merchantID uint, req *CreateProductRequest) (*Product, error)
+	CreateProduct(merchantID uint, staffID uint, req *CreateProductRequest) (*Product, error)
 	GetProductByID(id uint) (*Product, error)
-	SearchProducts(filter *SearchFilter) ([]Product, int64, error)
-	UpdateProduct(id uint, merchantID uint, req *UpdateProductRequest) error
+	SearchProducts(filter *SearchFilter) (*SearchResult, error)
+	UpdateProduct(id uint, merchantID uint, staffID uint, req *UpdateProductRequest) error
 	DeleteProduct(id uint, merchantID uint) error
-	GetMerchantProducts(merchantID uint) ([]Product, error)
+	UnlistProduct(adminID uint, id uint, reason string) (*Product, error)
+	RelistProduct(adminID uint, id uint) (*Product, error)
+	GetMerchantProducts(merchantID uint, page pagination.Page) ([]Product, string, error)
 	UpdateStock(id uint, quantity int) error
+	GetPriceHistory(id uint, merchantID uint, page pagination.Page) ([]PriceChange, string, error)
+	GetMarkdownRules(merchantID uint) ([]MarkdownRule, error)
+	SetMarkdownRules(merchantID uint, req *SetMarkdownRulesRequest) ([]MarkdownRule, error)
+	ExpireProducts() (int, error)
+	ApplyMarkdowns() (int, error)
+}
+
+// ImageService defines the interface for managing product images
+type ImageService interface {
+	AddImage(productID uint, merchantID uint, upload *ImageUpload) (*Image, error)
+	UpdateImage(productID uint, imageID uint, merchantID uint, req *UpdateImageRequest) (*Image, error)
+	DeleteImage(productID uint, imageID uint, merchantID uint) error
+	ReorderImages(productID uint, merchantID uint, req *ReorderImagesRequest) ([]Image, error)
 }
@@ -0,0 +1,8 @@
+package rbac
+
+import "github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+
+// Errors returned by permission checks
+var (
+	ErrPermissionDenied = apperror.Forbidden("permission_denied", "you do not have permission to do this")
+)
@@ -0,0 +1,103 @@
+package rbac
+
+// Platform roles, stored on auth.User.Role
+const (
+	RoleCustomer = "customer"
+	RoleMerchant = "merchant"
+	RoleAdmin    = "admin"
+)
+
+// Shop roles, held by the users working for a merchant
+const (
+	RoleOwner   = "owner"
+	RoleManager = "manager"
+	RoleCashier = "cashier"
+)
+
+// Permission allows an action, named resource:action
+type Permission string
+
+// Shop permissions
+const (
+	OrdersRead    Permission = "orders:read"
+	OrdersManage  Permission = "orders:manage" // confirm, mark ready, reject
+	OrdersRedeem  Permission = "orders:redeem"
+	ProductsRead  Permission = "products:read"
+	ProductsWrite Permission = "products:write" // includes prices and markdown rules
+	ShopRead      Permission = "shop:read"
+	ShopManage    Permission = "shop:manage" // profile and verification
+	StaffManage   Permission = "staff:manage"
+)
+
+// Admin permissions
+const (
+	UsersManage      Permission = "users:manage"
+	MerchantsList    Permission = "merchants:list"
+	MerchantsReview  Permission = "merchants:review"
+	OrdersModerate   Permission = "orders:moderate"
+	ProductsModerate Permission = "products:moderate"
+	CategoriesManage Permission = "categories:manage"
+	StatsRead        Permission = "stats:read"
+	AuditRead        Permission = "audit:read"
+)
+
+var cashierPermissions = []Permission{
+	OrdersRead, OrdersRedeem, ProductsRead, ShopRead,
+}
+
+var managerPermissions = append([]Permission{
+	OrdersManage, ProductsWrite,
+}, cashierPermissions...)
+
+var ownerPermissions = append([]Permission{
+	ShopManage, StaffManage,
+}, managerPermissions...)
+
+// policy lists the permissions granted to each role. Platform roles only
+// carry platform-wide permissions; shop permissions come from the role in
+// the shop.
+var policy = map[string][]Permission{
+	RoleCustomer: nil,
+	RoleMerchant: nil,
+	RoleAdmin: {
+		UsersManage, MerchantsList, MerchantsReview, OrdersModerate,
+		ProductsModerate, CategoriesManage, StatsRead, AuditRead,
+	},
+	RoleOwner:   ownerPermissions,
+	RoleManager: managerPermissions,
+	RoleCashier: cashierPermissions,
+}
+
+// grants indexes the policy for lookups
+var grants = func() map[string]map[Permission]bool {
+	index := make(map[string]map[Permission]bool, len(policy))
+	for role, permissions := range policy {
+		index[role] = make(map[Permission]bool, len(permissions))
+		for _, permission := range permissions {
+			index[role][permission] = true
+		}
+	}
+	return index
+}()
+
+// Allows reports whether the role grants the permission. Unknown roles
+// grant nothing.
+func Allows(role string, permission Permission) bool {
+	return grants[role][permission]
+}
+
+// Permissions lists the permissions granted to the role
+func Permissions(role string) []Permission {
+	return append([]Permission(nil), policy[role]...)
+}
+
+// Known reports whether any role grants the permission, catching typos in
+// route setup
+func Known(permission Permission) bool {
+	for _, permissions := range grants {
+		if permissions[permission] {
+			return true
+		}
+	}
+	return false
+}
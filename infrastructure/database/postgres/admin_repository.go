@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+	"time"
+)
+
+type adminRepository struct {
+	db *gorm.DB
+}
+
+// NewAdminRepository creates a new instance of admin repository
+func NewAdminRepository(db *gorm.DB) admin.Repository {
+	return &adminRepository{db: db}
+}
+
+// WithTrx returns a repository bound to the given transaction
+func (r *adminRepository) WithTrx(trxHandle *gorm.DB) admin.Repository {
+	if trxHandle == nil {
+		return r
+	}
+	return &adminRepository{db: trxHandle}
+}
+
+// CreateAuditLog records an admin action
+func (r *adminRepository) CreateAuditLog(log *admin.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+// FindAuditLogs finds a page of the audit log matching the filter, newest first
+func (r *adminRepository) FindAuditLogs(filter *admin.AuditFilter, page pagination.Page) ([]admin.AuditLog, string, error) {
+	query := r.db.Model(&admin.AuditLog{})
+	if filter.AdminID > 0 {
+		query = query.Where("admin_audit_logs.admin_id = ?", filter.AdminID)
+	}
+	if filter.Action != "" {
+		query = query.Where("admin_audit_logs.action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("admin_audit_logs.target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID > 0 {
+		query = query.Where("admin_audit_logs.target_id = ?", filter.TargetID)
+	}
+
+	query, err := newestFirst(query, "admin_audit_logs", page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var logs []admin.AuditLog
+	if err := query.Find(&logs).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(logs) > page.Limit {
+		logs = logs[:page.Limit]
+		last := logs[len(logs)-1]
+		next = pagination.TimeCursor(sortNewest, last.CreatedAt, last.ID)
+	}
+
+	return logs, next, nil
+}
+
+// GetStats counts the users, merchants, products and orders of the platform
+func (r *adminRepository) GetStats() (*admin.Stats, error) {
+	stats := &admin.Stats{OrdersByStatus: make(map[order.Status]int64)}
+	now := time.Now()
+
+	counts := []struct {
+		target *int64
+		query  *gorm.DB
+	}{
+		{&stats.Users, r.db.Model(&auth.User{})},
+		{&stats.ActiveUsers, r.db.Model(&auth.User{}).Where("is_active = ?", true)},
+		{&stats.Merchants, r.db.Model(&merchant.Merchant{})},
+		{&stats.VerifiedMerchants, r.db.Model(&merchant.Merchant{}).Where("is_verified = ?", true)},
+		{&stats.PendingMerchants, r.db.Model(&merchant.Merchant{}).Where("verification_status = ?", merchant.VerificationPending)},
+		{&stats.Products, r.db.Model(&product.Product{})},
+		{&stats.ListedProducts, listed(r.db.Model(&product.Product{})).
+			Where("products.is_active = ? AND products.expiry_date > ?", true, now)},
+		{&stats.OrdersLast24h, r.db.Model(&order.Order{}).Where("created_at >= ?", now.Add(-24*time.Hour))},
+	}
+	for _, count := range counts {
+		if err := count.query.Count(count.target).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var byStatus []struct {
+		Status order.Status
+		Count  int64
+	}
+	err := r.db.Model(&order.Order{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&byStatus).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range byStatus {
+		stats.OrdersByStatus[row.Status] = row.Count
+		stats.Orders += row.Count
+	}
+
+	err = r.db.Model(&order.Order{}).
+		Where("status = ?", order.StatusCompleted).
+		Select("COALESCE(SUM(total_amount), 0)").
+		Scan(&stats.Revenue).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -3,7 +3,9 @@ package postgres
 import (
 	"errors"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
 	"gorm.io/gorm"
+	"time"
 )
 
 type authRepository struct {
@@ -15,6 +17,14 @@ func NewAuthRepository(db *gorm.DB) auth.Repository {
 	return &authRepository{db: db}
 }
 
+// WithTrx returns a repository bound to the given transaction
+func (r *authRepository) WithTrx(trxHandle *gorm.DB) auth.Repository {
+	if trxHandle == nil {
+		return r
+	}
+	return &authRepository{db: trxHandle}
+}
+
 // CreateUser creates a new user
 func (r *authRepository) CreateUser(user *auth.User) error {
 	return r.db.Create(user).Error
@@ -26,7 +36,7 @@ func (r *authRepository) FindUserByEmail(email string) (*auth.User, error) {
 	err := r.db.Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, auth.ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -39,7 +49,7 @@ func (r *authRepository) FindUserByID(id uint) (*auth.User, error) {
 	err := r.db.First(&user, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, auth.ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -51,27 +61,96 @@ func (r *authRepository) UpdateUser(user *auth.User) error {
 	return r.db.Save(user).Error
 }
 
+// FindUsers finds a page of users matching the filter, newest first
+func (r *authRepository) FindUsers(filter *auth.UserFilter, page pagination.Page) ([]auth.User, string, error) {
+	query := r.db.Model(&auth.User{})
+	if filter.Query != "" {
+		pattern := containsPattern(filter.Query)
+		query = query.Where("users.email LIKE ? OR users.name LIKE ? OR users.phone LIKE ?", pattern, pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("users.role = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("users.is_active = ?", *filter.IsActive)
+	}
+
+	query, err := newestFirst(query, "users", page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var users []auth.User
+	if err := query.Find(&users).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(users) > page.Limit {
+		users = users[:page.Limit]
+		last := users[len(users)-1]
+		next = pagination.TimeCursor(sortNewest, last.CreatedAt, last.ID)
+	}
+
+	return users, next, nil
+}
+
 // CreateSession creates a new session
 func (r *authRepository) CreateSession(session *auth.Session) error {
 	return r.db.Create(session).Error
 }
 
-// FindSessionByToken finds a session by access token
-func (r *authRepository) FindSessionByToken(token string) (*auth.Session, error) {
+// FindSessionByTokenHash finds a session by the hash of its access token
+func (r *authRepository) FindSessionByTokenHash(hash string) (*auth.Session, error) {
 	var session auth.Session
-	err := r.db.Preload("User").Where("access_token = ?", token).First(&session).Error
+	err := r.db.Preload("User").Where("access_token_hash = ?", hash).First(&session).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("session not found")
+			return nil, auth.ErrSessionNotFound
 		}
 		return nil, err
 	}
 	return &session, nil
 }
 
-// DeleteSession deletes a session by token
-func (r *authRepository) DeleteSession(token string) error {
-	return r.db.Where("access_token = ?", token).Delete(&auth.Session{}).Error
+// FindSessionByRefreshTokenHash finds a session by the hash of its refresh token
+func (r *authRepository) FindSessionByRefreshTokenHash(hash string) (*auth.Session, error) {
+	var session auth.Session
+	err := r.db.Preload("User").Where("refresh_token_hash = ?", hash).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RotateSession marks a session as rotated and creates its successor. It
+// fails with auth.ErrRefreshReused if the session was rotated concurrently.
+func (r *authRepository) RotateSession(oldID uint, next *auth.Session) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&auth.Session{}).
+			Where("id = ? AND rotated_at IS NULL", oldID).
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return auth.ErrRefreshReused
+		}
+		return tx.Create(next).Error
+	})
+}
+
+// DeleteSessionFamily deletes every session rotated from the same login
+func (r *authRepository) DeleteSessionFamily(familyID string) error {
+	return r.db.Where("family_id = ?", familyID).Delete(&auth.Session{}).Error
+}
+
+// DeleteSessionByTokenHash deletes a session by the hash of its access token
+func (r *authRepository) DeleteSessionByTokenHash(hash string) error {
+	return r.db.Where("access_token_hash = ?", hash).Delete(&auth.Session{}).Error
 }
 
 // DeleteUserSessions deletes all sessions for a user
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/category"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new instance of category repository
+func NewCategoryRepository(db *gorm.DB) category.Repository {
+	return &categoryRepository{db: db}
+}
+
+// WithTrx returns a repository bound to the given transaction
+func (r *categoryRepository) WithTrx(trxHandle *gorm.DB) category.Repository {
+	if trxHandle == nil {
+		return r
+	}
+	return &categoryRepository{db: trxHandle}
+}
+
+// FindAll finds all categories in display order
+func (r *categoryRepository) FindAll() ([]category.Category, error) {
+	var categories []category.Category
+	if err := r.db.Order("position, id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// FindByID finds a category by ID
+func (r *categoryRepository) FindByID(id uint) (*category.Category, error) {
+	var cat category.Category
+	err := r.db.First(&cat, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, category.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// FindBySlug finds a category by slug
+func (r *categoryRepository) FindBySlug(slug string) (*category.Category, error) {
+	var cat category.Category
+	err := r.db.Where("slug = ?", slug).First(&cat).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, category.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// Create creates a new category
+func (r *categoryRepository) Create(cat *category.Category) error {
+	return r.db.Create(cat).Error
+}
+
+// Update updates a category
+func (r *categoryRepository) Update(cat *category.Category) error {
+	return r.db.Save(cat).Error
+}
+
+// Delete deletes a category
+func (r *categoryRepository) Delete(id uint) error {
+	return r.db.Delete(&category.Category{}, id).Error
+}
+
+// CountProducts counts the products of a category, active or not
+func (r *categoryRepository) CountProducts(slug string) (int64, error) {
+	var count int64
+	err := r.db.Model(&product.Product{}).Where("category = ?", slug).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,11 @@
+package postgres
+
+import "strings"
+
+// likeEscaper escapes the wildcards of LIKE so user input matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern gives the LIKE pattern matching values that contain s
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
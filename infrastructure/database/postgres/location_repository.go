@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/location"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type locationRepository struct {
@@ -15,6 +16,14 @@ func NewLocationRepository(db *gorm.DB) location.Repository {
 	return &locationRepository{db: db}
 }
 
+// WithTrx returns a repository bound to the given transaction
+func (r *locationRepository) WithTrx(trxHandle *gorm.DB) location.Repository {
+	if trxHandle == nil {
+		return r
+	}
+	return &locationRepository{db: trxHandle}
+}
+
 // Create creates a new location
 func (r *locationRepository) Create(loc *location.Location) error {
 	return r.db.Create(loc).Error
@@ -26,7 +35,7 @@ func (r *locationRepository) FindByID(id uint) (*location.Location, error) {
 	err := r.db.First(&loc, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("location not found")
+			return nil, location.ErrLocationNotFound
 		}
 		return nil, err
 	}
@@ -43,6 +52,20 @@ func (r *locationRepository) FindByUserID(userID uint) ([]location.Location, err
 	return locations, nil
 }
 
+// LockUserLocations finds all locations of a user and locks them until the
+// surrounding transaction ends, so concurrent default changes are serialized
+func (r *locationRepository) LockUserLocations(userID uint) ([]location.Location, error) {
+	var locations []location.Location
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at DESC").
+		Find(&locations).Error
+	if err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
 // Update updates a location
 func (r *locationRepository) Update(loc *location.Location) error {
 	return r.db.Save(loc).Error
@@ -62,8 +85,12 @@ func (r *locationRepository) SetDefaultLocation(userID uint, locationID uint) er
 			return err
 		}
 		// Set the specified location as default
-		if err := tx.Model(&location.Location{}).Where("id = ? AND user_id = ?", locationID, userID).Update("is_default", true).Error; err != nil {
-			return err
+		result := tx.Model(&location.Location{}).Where("id = ? AND user_id = ?", locationID, userID).Update("is_default", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return location.ErrLocationNotFound
 		}
 		return nil
 	})
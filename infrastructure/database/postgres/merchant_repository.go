@@ -3,9 +3,26 @@ package postgres
 import (
 	"errors"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// verificationColumns are only written by UpdateVerification, so a profile
+// update cannot undo a concurrent review
+var verificationColumns = []string{
+	"is_verified",
+	"verification_status",
+	"verification_note",
+	"submitted_at",
+	"reviewed_at",
+	"reviewed_by",
+}
+
+// openMemberStatuses are the memberships still in use: pending invitations
+// and active members
+var openMemberStatuses = []string{merchant.MemberInvited, merchant.MemberActive}
+
 type merchantRepository struct {
 	db *gorm.DB
 }
@@ -15,9 +32,17 @@ func NewMerchantRepository(db *gorm.DB) merchant.Repository {
 	return &merchantRepository{db: db}
 }
 
+// WithTrx returns a repository bound to the given transaction
+func (r *merchantRepository) WithTrx(trxHandle *gorm.DB) merchant.Repository {
+	if trxHandle == nil {
+		return r
+	}
+	return &merchantRepository{db: trxHandle}
+}
+
 // Create creates a new merchant
 func (r *merchantRepository) Create(merch *merchant.Merchant) error {
-	return r.db.Create(merch).Error
+	return r.db.Omit(clause.Associations).Create(merch).Error
 }
 
 // FindByID finds a merchant by ID
@@ -26,29 +51,44 @@ func (r *merchantRepository) FindByID(id uint) (*merchant.Merchant, error) {
 	err := r.db.First(&merch, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("merchant not found")
+			return nil, merchant.ErrMerchantNotFound
 		}
 		return nil, err
 	}
 	return &merch, nil
 }
 
+// FindByIDs finds merchants by their IDs
+func (r *merchantRepository) FindByIDs(ids []uint) ([]merchant.Merchant, error) {
+	var merchants []merchant.Merchant
+	if len(ids) == 0 {
+		return merchants, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&merchants).Error
+	if err != nil {
+		return nil, err
+	}
+	return merchants, nil
+}
+
 // FindByUserID finds a merchant by user ID
 func (r *merchantRepository) FindByUserID(userID uint) (*merchant.Merchant, error) {
 	var merch merchant.Merchant
 	err := r.db.Where("user_id = ?", userID).First(&merch).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("merchant not found")
+			return nil, merchant.ErrMerchantNotFound
 		}
 		return nil, err
 	}
 	return &merch, nil
 }
 
-// Update updates a merchant
+// Update updates a merchant's profile. The verification is saved with
+// UpdateVerification.
 func (r *merchantRepository) Update(merch *merchant.Merchant) error {
-	return r.db.Save(merch).Error
+	omit := append([]string{clause.Associations}, verificationColumns...)
+	return r.db.Omit(omit...).Save(merch).Error
 }
 
 // Delete deletes a merchant (soft delete by setting is_active to false)
@@ -65,3 +105,219 @@ func (r *merchantRepository) FindAll() ([]merchant.Merchant, error) {
 	}
 	return merchants, nil
 }
+
+// FindMerchants finds a page of merchants matching the filter, newest first
+func (r *merchantRepository) FindMerchants(filter *merchant.Filter, page pagination.Page) ([]merchant.Merchant, string, error) {
+	query := r.db.Model(&merchant.Merchant{})
+	if filter.Query != "" {
+		pattern := containsPattern(filter.Query)
+		query = query.Where("merchants.shop_name LIKE ? OR merchants.phone LIKE ?", pattern, pattern)
+	}
+	if filter.VerificationStatus != "" {
+		query = query.Where("merchants.verification_status = ?", filter.VerificationStatus)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("merchants.is_active = ?", *filter.IsActive)
+	}
+
+	query, err := newestFirst(query, "merchants", page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var merchants []merchant.Merchant
+	if err := query.Find(&merchants).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(merchants) > page.Limit {
+		merchants = merchants[:page.Limit]
+		last := merchants[len(merchants)-1]
+		next = pagination.TimeCursor(sortNewest, last.CreatedAt, last.ID)
+	}
+
+	return merchants, next, nil
+}
+
+// FindPendingReview finds the merchants waiting for review with their
+// documents, first submitted first
+func (r *merchantRepository) FindPendingReview() ([]merchant.Merchant, error) {
+	var merchants []merchant.Merchant
+	err := r.db.Preload("Documents", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, id")
+	}).
+		Where("verification_status = ?", merchant.VerificationPending).
+		Order("submitted_at, id").
+		Find(&merchants).Error
+	if err != nil {
+		return nil, err
+	}
+	return merchants, nil
+}
+
+// UpdateVerification saves the verification of a merchant whose status is
+// still from. It fails if the status was changed concurrently.
+func (r *merchantRepository) UpdateVerification(merch *merchant.Merchant, from string) error {
+	result := r.db.Model(&merchant.Merchant{}).
+		Where("id = ? AND verification_status = ?", merch.ID, from).
+		Updates(map[string]interface{}{
+			"is_verified":         merch.IsVerified,
+			"verification_status": merch.VerificationStatus,
+			"verification_note":   merch.VerificationNote,
+			"submitted_at":        merch.SubmittedAt,
+			"reviewed_at":         merch.ReviewedAt,
+			"reviewed_by":         merch.ReviewedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return merchant.ErrVerificationChanged
+	}
+	return nil
+}
+
+// CreateDocument creates a KYC document
+func (r *merchantRepository) CreateDocument(document *merchant.Document) error {
+	return r.db.Create(document).Error
+}
+
+// FindDocuments finds the KYC documents of a merchant, oldest first
+func (r *merchantRepository) FindDocuments(merchantID uint) ([]merchant.Document, error) {
+	var documents []merchant.Document
+	err := r.db.Where("merchant_id = ?", merchantID).Order("created_at, id").Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// FindDocument finds a KYC document of a merchant
+func (r *merchantRepository) FindDocument(merchantID uint, documentID uint) (*merchant.Document, error) {
+	var document merchant.Document
+	err := r.db.Where("id = ? AND merchant_id = ?", documentID, merchantID).First(&document).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, merchant.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return &document, nil
+}
+
+// CreateMember creates a shop member or invitation
+func (r *merchantRepository) CreateMember(member *merchant.Member) error {
+	return r.db.Omit(clause.Associations).Create(member).Error
+}
+
+// FindMember finds a current member or pending invitation of a merchant
+func (r *merchantRepository) FindMember(merchantID uint, id uint) (*merchant.Member, error) {
+	var member merchant.Member
+	err := r.db.Where("id = ? AND merchant_id = ? AND status IN ?", id, merchantID, openMemberStatuses).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, merchant.ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindMembers finds the current members and pending invitations of a
+// merchant, oldest first
+func (r *merchantRepository) FindMembers(merchantID uint) ([]merchant.Member, error) {
+	var members []merchant.Member
+	err := r.db.Where("merchant_id = ? AND status IN ?", merchantID, openMemberStatuses).
+		Order("created_at, id").
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// FindOpenMemberByEmail finds the current member or pending invitation of a
+// merchant with the email
+func (r *merchantRepository) FindOpenMemberByEmail(merchantID uint, email string) (*merchant.Member, error) {
+	var member merchant.Member
+	err := r.db.Where("merchant_id = ? AND email = ? AND status IN ?", merchantID, email, openMemberStatuses).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, merchant.ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindActiveMemberByUserID finds the membership of a user in the shop they
+// work for
+func (r *merchantRepository) FindActiveMemberByUserID(userID uint) (*merchant.Member, error) {
+	var member merchant.Member
+	err := r.db.Where("user_id = ? AND status = ?", userID, merchant.MemberActive).
+		Order("id").
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, merchant.ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindInvitations finds the pending invitations sent to the email with their
+// shop, newest first
+func (r *merchantRepository) FindInvitations(email string) ([]merchant.Member, error) {
+	var invitations []merchant.Member
+	err := r.db.Preload("Merchant").
+		Where("email = ? AND status = ?", email, merchant.MemberInvited).
+		Order("created_at DESC, id DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// FindInvitation finds a pending invitation sent to the email
+func (r *merchantRepository) FindInvitation(id uint, email string) (*merchant.Member, error) {
+	var invitation merchant.Member
+	err := r.db.Preload("Merchant").
+		Where("id = ? AND email = ? AND status = ?", id, email, merchant.MemberInvited).
+		First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, merchant.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// UpdateMember saves a member whose status is still from. It fails if the
+// status was changed concurrently, e.g. an invitation revoked while it was
+// being accepted.
+func (r *merchantRepository) UpdateMember(member *merchant.Member, from string) error {
+	result := r.db.Model(&merchant.Member{}).
+		Where("id = ? AND status = ?", member.ID, from).
+		Updates(map[string]interface{}{
+			"user_id":               member.UserID,
+			"role":                  member.Role,
+			"status":                member.Status,
+			"accepted_at":           member.AcceptedAt,
+			"revoked_at":            member.RevokedAt,
+			"revoked_by":            member.RevokedBy,
+			"invitation_token_hash": member.InvitationTokenHash,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return merchant.ErrMemberChanged
+	}
+	return nil
+}
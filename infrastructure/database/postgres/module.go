@@ -1,9 +1,12 @@
 package postgres
 
 import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/category"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/location"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/notification"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
 	"go.uber.org/fx"
@@ -41,4 +44,22 @@ var Module = fx.Options(
 			fx.As(new(location.Repository)),
 		),
 	),
+	fx.Provide(
+		fx.Annotate(
+			NewCategoryRepository,
+			fx.As(new(category.Repository)),
+		),
+	),
+	fx.Provide(
+		fx.Annotate(
+			NewNotificationRepository,
+			fx.As(new(notification.Repository)),
+		),
+	),
+	fx.Provide(
+		fx.Annotate(
+			NewAdminRepository,
+			fx.As(new(admin.Repository)),
+		),
+	),
 )
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/notification"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+	"time"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new instance of notification repository
+func NewNotificationRepository(db *gorm.DB) notification.Repository {
+	return &notificationRepository{db: db}
+}
+
+// Create creates a new notification
+func (r *notificationRepository) Create(n *notification.Notification) error {
+	return r.db.Create(n).Error
+}
+
+// FindByUserID finds a page of a user's notifications, newest first
+func (r *notificationRepository) FindByUserID(userID uint, page pagination.Page) ([]notification.Notification, string, error) {
+	query, err := newestFirst(r.db.Model(&notification.Notification{}).Where("notifications.user_id = ?", userID), "notifications", page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var notifications []notification.Notification
+	if err := query.Find(&notifications).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(notifications) > page.Limit {
+		notifications = notifications[:page.Limit]
+		last := notifications[len(notifications)-1]
+		next = pagination.TimeCursor(sortNewest, last.CreatedAt, last.ID)
+	}
+
+	return notifications, next, nil
+}
+
+// MarkRead marks a user's notification as read. It returns false if the
+// user has no such notification.
+func (r *notificationRepository) MarkRead(userID uint, id uint) (bool, error) {
+	var n notification.Notification
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&n).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if n.ReadAt != nil {
+		return true, nil
+	}
+
+	now := time.Now()
+	return true, r.db.Model(&n).Update("read_at", &now).Error
+}
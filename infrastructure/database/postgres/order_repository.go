@@ -3,7 +3,9 @@ package postgres
 import (
 	"errors"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
 	"gorm.io/gorm"
+	"time"
 )
 
 type orderRepository struct {
@@ -15,6 +17,14 @@ func NewOrderRepository(db *gorm.DB) order.Repository {
 	return &orderRepository{db: db}
 }
 
+// WithTrx returns a repository bound to the given transaction
+func (r *orderRepository) WithTrx(trxHandle *gorm.DB) order.Repository {
+	if trxHandle == nil {
+		return r
+	}
+	return &orderRepository{db: trxHandle}
+}
+
 // CreateOrder creates a new order
 func (r *orderRepository) CreateOrder(ord *order.Order) error {
 	return r.db.Create(ord).Error
@@ -26,7 +36,7 @@ func (r *orderRepository) FindOrderByID(id uint) (*order.Order, error) {
 	err := r.db.Preload("Items").First(&ord, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("order not found")
+			return nil, order.ErrOrderNotFound
 		}
 		return nil, err
 	}
@@ -39,36 +49,116 @@ func (r *orderRepository) FindOrderByCode(code string) (*order.Order, error) {
 	err := r.db.Preload("Items").Where("order_code = ?", code).First(&ord).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("order not found")
+			return nil, order.ErrOrderNotFound
 		}
 		return nil, err
 	}
 	return &ord, nil
 }
 
-// FindOrdersByUserID finds all orders by user ID
-func (r *orderRepository) FindOrdersByUserID(userID uint) ([]order.Order, error) {
-	var orders []order.Order
-	err := r.db.Preload("Items").Where("user_id = ?", userID).Order("created_at DESC").Find(&orders).Error
+// FindOrdersByUserID finds a page of a user's orders, newest first
+func (r *orderRepository) FindOrdersByUserID(userID uint, page pagination.Page) ([]order.Order, string, error) {
+	return r.findOrdersPage(r.db.Where("orders.user_id = ?", userID), page)
+}
+
+// FindOrdersByMerchantID finds a page of a merchant's orders, newest first
+func (r *orderRepository) FindOrdersByMerchantID(merchantID uint, page pagination.Page) ([]order.Order, string, error) {
+	return r.findOrdersPage(r.db.Where("orders.merchant_id = ?", merchantID), page)
+}
+
+// FindOrders finds a page of orders matching the filter, newest first
+func (r *orderRepository) FindOrders(filter *order.Filter, page pagination.Page) ([]order.Order, string, error) {
+	query := r.db
+	if filter.Query != "" {
+		query = query.Where("orders.order_code LIKE ?", containsPattern(filter.Query))
+	}
+	if filter.Status != "" {
+		query = query.Where("orders.status = ?", filter.Status)
+	}
+	if filter.UserID > 0 {
+		query = query.Where("orders.user_id = ?", filter.UserID)
+	}
+	if filter.MerchantID > 0 {
+		query = query.Where("orders.merchant_id = ?", filter.MerchantID)
+	}
+	return r.findOrdersPage(query, page)
+}
+
+// findOrdersPage loads one page of orders with their items and the cursor
+// of the next page, which is empty on the last page
+func (r *orderRepository) findOrdersPage(query *gorm.DB, page pagination.Page) ([]order.Order, string, error) {
+	query, err := newestFirst(query.Model(&order.Order{}), "orders", page)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return orders, nil
+
+	var orders []order.Order
+	if err := query.Preload("Items").Find(&orders).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(orders) > page.Limit {
+		orders = orders[:page.Limit]
+		last := orders[len(orders)-1]
+		next = pagination.TimeCursor(sortNewest, last.CreatedAt, last.ID)
+	}
+
+	return orders, next, nil
+}
+
+// UpdateOrder updates an order
+func (r *orderRepository) UpdateOrder(ord *order.Order) error {
+	return r.db.Save(ord).Error
+}
+
+// UpdateOrderStatus saves an order whose status moved from the given status
+// and records the transition. It fails if the order was changed concurrently.
+func (r *orderRepository) UpdateOrderStatus(ord *order.Order, from order.Status, history *order.StatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&order.Order{}).
+			Where("id = ? AND status = ?", ord.ID, from).
+			Updates(map[string]interface{}{
+				"status":         ord.Status,
+				"payment_status": ord.PaymentStatus,
+				"confirmed_at":   ord.ConfirmedAt,
+				"ready_at":       ord.ReadyAt,
+				"completed_at":   ord.CompletedAt,
+				"cancelled_at":   ord.CancelledAt,
+				"cancel_reason":  ord.CancelReason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return order.ErrOrderChanged
+		}
+		return tx.Create(history).Error
+	})
 }
 
-// FindOrdersByMerchantID finds all orders by merchant ID
-func (r *orderRepository) FindOrdersByMerchantID(merchantID uint) ([]order.Order, error) {
+// FindExpiredOrders finds open orders whose pickup time is before the given time
+func (r *orderRepository) FindExpiredOrders(pickupBefore time.Time) ([]order.Order, error) {
 	var orders []order.Order
-	err := r.db.Preload("Items").Where("merchant_id = ?", merchantID).Order("created_at DESC").Find(&orders).Error
+	err := r.db.Preload("Items").
+		Where("status IN ? AND pickup_time < ?", []order.Status{order.StatusPending, order.StatusConfirmed, order.StatusReady}, pickupBefore).
+		Find(&orders).Error
 	if err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
-// UpdateOrder updates an order
-func (r *orderRepository) UpdateOrder(ord *order.Order) error {
-	return r.db.Save(ord).Error
+// MarkStockReleased flags the order's stock as released. It returns false if
+// the stock had already been released.
+func (r *orderRepository) MarkStockReleased(orderID uint) (bool, error) {
+	result := r.db.Model(&order.Order{}).
+		Where("id = ? AND stock_released = ?", orderID, false).
+		Update("stock_released", true)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
 }
 
 // CreateCart creates a new cart
@@ -117,6 +207,11 @@ func (r *orderRepository) RemoveCartItem(id uint) error {
 	return r.db.Delete(&order.CartItem{}, id).Error
 }
 
+// RemoveCartItems removes the given items from a cart
+func (r *orderRepository) RemoveCartItems(cartID uint, ids []uint) error {
+	return r.db.Where("cart_id = ? AND id IN ?", cartID, ids).Delete(&order.CartItem{}).Error
+}
+
 // ClearCart clears all items from cart
 func (r *orderRepository) ClearCart(cartID uint) error {
 	return r.db.Where("cart_id = ?", cartID).Delete(&order.CartItem{}).Error
@@ -138,7 +233,7 @@ func (r *orderRepository) FindCartItemByID(id uint) (*order.CartItem, error) {
 	err := r.db.First(&item, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("cart item not found")
+			return nil, order.ErrCartItemNotFound
 		}
 		return nil, err
 	}
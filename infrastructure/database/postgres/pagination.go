@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+)
+
+// sortNewest is the cursor sort of lists ordered by creation time
+const sortNewest = "newest"
+
+// seekAfter restricts a query ordered by key and then id descending to the
+// rows following the cursor position. keyVars bind placeholders in key.
+func seekAfter(query *gorm.DB, key string, desc bool, idColumn string, value interface{}, id uint, keyVars ...interface{}) *gorm.DB {
+	cmp := ">"
+	if desc {
+		cmp = "<"
+	}
+
+	vars := append([]interface{}{}, keyVars...)
+	vars = append(vars, value)
+	vars = append(vars, keyVars...)
+	vars = append(vars, value, id)
+
+	return query.Where("("+key+" "+cmp+" ? OR ("+key+" "+cmp+"= ? AND "+idColumn+" < ?))", vars...)
+}
+
+// newestFirst orders a query by created_at and id descending, starting after
+// the page cursor. One extra row is fetched to tell whether a next page exists.
+func newestFirst(query *gorm.DB, table string, page pagination.Page) (*gorm.DB, error) {
+	after, err := page.AfterSort(sortNewest)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		if after.Time == nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+		query = seekAfter(query, table+".created_at", true, table+".id", *after.Time, after.ID)
+	}
+
+	return query.Order(table + ".created_at DESC").Order(table + ".id DESC").Limit(page.Limit + 1), nil
+}
@@ -3,9 +3,25 @@ package postgres
 import (
 	"errors"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// earthRadiusKm is the mean radius of the earth used for distances
+const earthRadiusKm = 6371.0
+
+// distanceSQL computes the haversine distance in km between a merchant and
+// the point bound to its placeholders (lat, lat, lng)
+const distanceSQL = "2 * 6371 * ASIN(LEAST(1, SQRT(" +
+	"POW(SIN(RADIANS(merchants.latitude - ?) / 2), 2) + " +
+	"COS(RADIANS(?)) * COS(RADIANS(merchants.latitude)) * " +
+	"POW(SIN(RADIANS(merchants.longitude - ?) / 2), 2))))"
+
 type productRepository struct {
 	db *gorm.DB
 }
@@ -15,6 +31,14 @@ func NewProductRepository(db *gorm.DB) product.Repository {
 	return &productRepository{db: db}
 }
 
+// WithTrx returns a repository bound to the given transaction
+func (r *productRepository) WithTrx(trxHandle *gorm.DB) product.Repository {
+	if trxHandle == nil {
+		return r
+	}
+	return &productRepository{db: trxHandle}
+}
+
 // Create creates a new product
 func (r *productRepository) Create(prod *product.Product) error {
 	return r.db.Create(prod).Error
@@ -23,67 +47,362 @@ func (r *productRepository) Create(prod *product.Product) error {
 // FindByID finds a product by ID
 func (r *productRepository) FindByID(id uint) (*product.Product, error) {
 	var prod product.Product
-	err := r.db.First(&prod, id).Error
+	err := withImages(r.db).First(&prod, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, product.ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &prod, nil
+}
+
+// FindListedByID finds a product listed publicly, i.e. of an active and
+// verified merchant
+func (r *productRepository) FindListedByID(id uint) (*product.Product, error) {
+	var prod product.Product
+	err := listed(withImages(r.db)).Select("products.*").First(&prod, "products.id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, product.ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &prod, nil
+}
+
+// LockByID finds a product by ID and locks its row until the end of the
+// transaction
+func (r *productRepository) LockByID(id uint) (*product.Product, error) {
+	var prod product.Product
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&prod, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, product.ErrProductNotFound
 		}
 		return nil, err
 	}
 	return &prod, nil
 }
 
-// FindAll finds all products with filters
-func (r *productRepository) FindAll(filter *product.SearchFilter) ([]product.Product, int64, error) {
+// withImages loads the images of the queried products, cover first
+func withImages(query *gorm.DB) *gorm.DB {
+	return query.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	})
+}
+
+// FindByIDs finds products by their IDs
+func (r *productRepository) FindByIDs(ids []uint) ([]product.Product, error) {
 	var products []product.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := withImages(r.db).Where("id IN ?", ids).Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Facets a search filter can leave out, so a facet is counted without its own filter
+const (
+	ignoreNone = iota
+	ignoreCategory
+	ignorePrice
+)
+
+// searchSort is the keyset of a search sort option: the sort key, its
+// direction and whether it is a timestamp. Ties are broken by id descending.
+type searchSort struct {
+	key    string
+	desc   bool
+	isTime bool
+}
+
+// searchSorts maps search sort options to their keysets. Relevance is ordered
+// by the position of the keyword hits instead.
+var searchSorts = map[string]searchSort{
+	product.SortNewest:    {key: "products.created_at", desc: true, isTime: true},
+	product.SortDiscount:  {key: "products.discount", desc: true},
+	product.SortPriceAsc:  {key: "products.sale_price"},
+	product.SortPriceDesc: {key: "products.sale_price", desc: true},
+	product.SortExpiry:    {key: "products.expiry_date", isTime: true},
+	product.SortDistance:  {key: distanceSQL},
+}
+
+// FindAll finds one page of products with filters, returning the cursor of
+// the next page, which is empty on the last page
+func (r *productRepository) FindAll(filter *product.SearchFilter) ([]product.Product, string, error) {
+	var products []product.Product
+
+	sortBy := filter.SortBy
+	if sortBy == "" || (sortBy == product.SortRelevance && len(filter.ProductIDs) == 0) {
+		sortBy = product.SortNewest
+	}
+
+	after, err := filter.Page.AfterSort(sortBy)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := r.filtered(filter, ignoreNone)
+
+	var distanceVars []interface{}
+	if filter.Near != nil {
+		distanceVars = []interface{}{filter.Near.Latitude, filter.Near.Latitude, filter.Near.Longitude}
+		query = query.Select("products.*, "+distanceSQL+" AS distance_km", distanceVars...)
+	} else {
+		query = query.Select("products.*")
+	}
+
+	if sortBy == product.SortRelevance {
+		// Hits are already ranked, so the cursor holds the rank of the last row
+		ids := filter.ProductIDs
+		if after != nil {
+			if after.Value == nil || *after.Value < 0 || int(*after.Value) > len(ids) {
+				return nil, "", pagination.ErrInvalidCursor
+			}
+			ids = ids[int(*after.Value):]
+			if len(ids) == 0 {
+				return []product.Product{}, "", nil
+			}
+			query = query.Where("products.id IN ?", ids)
+		}
+		query = query.Order(byPosition("products.id", ids))
+	} else {
+		spec, ok := searchSorts[sortBy]
+		if !ok {
+			return nil, "", product.ErrInvalidSort
+		}
+
+		var keyVars []interface{}
+		order := spec.key
+		if sortBy == product.SortDistance {
+			keyVars = distanceVars
+			order = "distance_km"
+		}
+
+		if after != nil {
+			var value interface{}
+			switch {
+			case spec.isTime && after.Time != nil:
+				value = *after.Time
+			case !spec.isTime && after.Value != nil:
+				value = *after.Value
+			default:
+				return nil, "", pagination.ErrInvalidCursor
+			}
+			query = seekAfter(query, spec.key, spec.desc, "products.id", value, after.ID, keyVars...)
+		}
+
+		if spec.desc {
+			order += " DESC"
+		}
+		query = query.Order(order)
+	}
+	query = query.Order("products.id DESC").Limit(filter.Page.Limit + 1)
+
+	if err := withImages(query).Find(&products).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(products) > filter.Page.Limit {
+		products = products[:filter.Page.Limit]
+		next = searchCursor(sortBy, &products[len(products)-1], filter.ProductIDs)
+	}
+
+	return products, next, nil
+}
+
+// searchCursor returns the cursor pointing after the given product
+func searchCursor(sortBy string, last *product.Product, ranked []uint) string {
+	switch sortBy {
+	case product.SortRelevance:
+		for i, id := range ranked {
+			if id == last.ID {
+				return pagination.ValueCursor(sortBy, float64(i+1), last.ID)
+			}
+		}
+		return ""
+	case product.SortDiscount:
+		return pagination.ValueCursor(sortBy, last.Discount, last.ID)
+	case product.SortPriceAsc, product.SortPriceDesc:
+		return pagination.ValueCursor(sortBy, last.SalePrice, last.ID)
+	case product.SortExpiry:
+		return pagination.TimeCursor(sortBy, last.ExpiryDate, last.ID)
+	case product.SortDistance:
+		if last.DistanceKm == nil {
+			return ""
+		}
+		return pagination.ValueCursor(sortBy, *last.DistanceKm, last.ID)
+	default:
+		return pagination.TimeCursor(sortBy, last.CreatedAt, last.ID)
+	}
+}
+
+// Count counts the products matching the filter
+func (r *productRepository) Count(filter *product.SearchFilter) (int64, error) {
 	var total int64
+	err := r.filtered(filter, ignoreNone).Count(&total).Error
+	return total, err
+}
+
+// Facets counts the products matching the filter per category and price bucket
+func (r *productRepository) Facets(filter *product.SearchFilter) (*product.Facets, error) {
+	facets := &product.Facets{
+		Categories:  []product.CategoryFacet{},
+		PriceRanges: make([]product.PriceFacet, len(product.PriceBuckets)),
+	}
+
+	err := r.filtered(filter, ignoreCategory).
+		Select("products.category AS category, COUNT(*) AS count").
+		Group("products.category").
+		Order("count DESC, category ASC").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []struct {
+		Bucket int
+		Count  int64
+	}
+	bucketSQL, bucketVars := priceBucketSQL("products.sale_price")
+	err = r.filtered(filter, ignorePrice).
+		Select(bucketSQL+" AS bucket, COUNT(*) AS count", bucketVars...).
+		Group("bucket").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i, bucket := range product.PriceBuckets {
+		facets.PriceRanges[i].PriceRange = bucket
+	}
+	for _, b := range buckets {
+		if b.Bucket >= 0 && b.Bucket < len(facets.PriceRanges) {
+			facets.PriceRanges[b.Bucket].Count = b.Count
+		}
+	}
 
-	query := r.db.Model(&product.Product{}).Where("is_active = ?", true)
+	return facets, nil
+}
+
+// filtered builds the product query for a search filter, leaving out the
+// filter of the facet being counted
+func (r *productRepository) filtered(filter *product.SearchFilter, ignore int) *gorm.DB {
+	now := time.Now()
+	query := r.db.Model(&product.Product{}).
+		Where("products.is_active = ? AND products.expiry_date > ?", true, now)
 
 	// Apply filters
-	if filter.Keyword != "" {
-		query = query.Where("name ILIKE ? OR description ILIKE ?", "%"+filter.Keyword+"%", "%"+filter.Keyword+"%")
+	if len(filter.ProductIDs) > 0 {
+		query = query.Where("products.id IN ?", filter.ProductIDs)
 	}
-	if filter.Category != "" {
-		query = query.Where("category = ?", filter.Category)
+	if len(filter.Categories) > 0 && ignore != ignoreCategory {
+		query = query.Where("products.category IN ?", filter.Categories)
 	}
-	if filter.MinPrice > 0 {
-		query = query.Where("sale_price >= ?", filter.MinPrice)
+	if filter.MinPrice > 0 && ignore != ignorePrice {
+		query = query.Where("products.sale_price >= ?", filter.MinPrice)
 	}
-	if filter.MaxPrice > 0 {
-		query = query.Where("sale_price <= ?", filter.MaxPrice)
+	if filter.MaxPrice > 0 && ignore != ignorePrice {
+		query = query.Where("products.sale_price <= ?", filter.MaxPrice)
 	}
 	if filter.MerchantID > 0 {
-		query = query.Where("merchant_id = ?", filter.MerchantID)
+		query = query.Where("products.merchant_id = ?", filter.MerchantID)
 	}
-
-	// Count total
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if filter.MinDiscount > 0 {
+		query = query.Where("products.discount >= ?", filter.MinDiscount)
+	}
+	if filter.ExpiringWithin > 0 {
+		query = query.Where("products.expiry_date BETWEEN ? AND ?", now, now.Add(filter.ExpiringWithin))
+	}
+	if filter.InStock {
+		query = query.Where("products.stock > 0")
 	}
 
-	// Apply pagination
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
+	query = listed(query)
+	if filter.Near != nil {
+		query = withinRadius(query, *filter.Near, filter.RadiusKm)
 	}
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
+
+	return query
+}
+
+// priceBucketSQL returns a CASE expression giving the index of the price
+// bucket of column
+func priceBucketSQL(column string) (string, []interface{}) {
+	var sql strings.Builder
+	var vars []interface{}
+
+	sql.WriteString("CASE")
+	for i, bucket := range product.PriceBuckets {
+		if bucket.Max == 0 {
+			sql.WriteString(" WHEN " + column + " >= ? THEN ?")
+			vars = append(vars, bucket.Min, i)
+			continue
+		}
+		sql.WriteString(" WHEN " + column + " >= ? AND " + column + " < ? THEN ?")
+		vars = append(vars, bucket.Min, bucket.Max, i)
 	}
+	sql.WriteString(" END")
 
-	// Order by created_at desc
-	query = query.Order("created_at DESC")
+	return sql.String(), vars
+}
 
-	err := query.Find(&products).Error
+// FindActive finds all active products
+func (r *productRepository) FindActive() ([]product.Product, error) {
+	var products []product.Product
+	err := r.db.Where("is_active = ?", true).Find(&products).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
+	return products, nil
+}
 
-	return products, total, nil
+// listed restricts a query to the products shown publicly: not unlisted by
+// an admin and of an active and verified merchant
+func listed(query *gorm.DB) *gorm.DB {
+	return query.Joins("JOIN merchants ON merchants.id = products.merchant_id").
+		Where("products.unlisted_at IS NULL").
+		Where("merchants.is_active = ? AND merchants.is_verified = ?", true, true)
+}
+
+// byPosition orders rows by the position of column in ids. The ids are
+// inlined because gorm drops expression orders followed by another order.
+func byPosition(column string, ids []uint) string {
+	var order strings.Builder
+	order.WriteString("FIELD(" + column)
+	for _, id := range ids {
+		order.WriteString(", " + strconv.FormatUint(uint64(id), 10))
+	}
+	order.WriteString(")")
+	return order.String()
 }
 
-// Update updates a product
+// withinRadius restricts a query joined with merchants to shops within
+// radiusKm of the point. A bounding box on the merchant coordinates narrows
+// the rows before the exact distance is checked.
+func withinRadius(query *gorm.DB, point product.GeoPoint, radiusKm float64) *gorm.DB {
+	latDelta := radiusKm / earthRadiusKm * 180 / math.Pi
+
+	query = query.Where("merchants.latitude BETWEEN ? AND ?", point.Latitude-latDelta, point.Latitude+latDelta)
+
+	// The longitude box degenerates near the poles, so it is only used where it is meaningful
+	if cosLat := math.Cos(point.Latitude * math.Pi / 180); cosLat > 0.01 {
+		lngDelta := latDelta / cosLat
+		query = query.Where("merchants.longitude BETWEEN ? AND ?", point.Longitude-lngDelta, point.Longitude+lngDelta)
+	}
+
+	return query.Where(distanceSQL+" <= ?", point.Latitude, point.Latitude, point.Longitude, radiusKm)
+}
+
+// Update updates a product. Its images are saved separately and the unlisting
+// only changes through Unlist and Relist.
 func (r *productRepository) Update(prod *product.Product) error {
-	return r.db.Save(prod).Error
+	return r.db.Omit(clause.Associations, "unlisted_at", "unlist_reason").Save(prod).Error
 }
 
 // Delete deletes a product (soft delete by setting is_active to false)
@@ -91,17 +410,229 @@ func (r *productRepository) Delete(id uint) error {
 	return r.db.Model(&product.Product{}).Where("id = ?", id).Update("is_active", false).Error
 }
 
+// Unlist takes a product down. It reports false if the product does not
+// exist or is already unlisted.
+func (r *productRepository) Unlist(id uint, reason string, at time.Time) (bool, error) {
+	result := r.db.Model(&product.Product{}).
+		Where("id = ? AND unlisted_at IS NULL", id).
+		Updates(map[string]interface{}{"unlisted_at": at, "unlist_reason": reason})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Relist puts an unlisted product back. It reports false if the product does
+// not exist or is not unlisted.
+func (r *productRepository) Relist(id uint) (bool, error) {
+	result := r.db.Model(&product.Product{}).
+		Where("id = ? AND unlisted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"unlisted_at": nil, "unlist_reason": ""})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // UpdateStock updates product stock
 func (r *productRepository) UpdateStock(id uint, quantity int) error {
 	return r.db.Model(&product.Product{}).Where("id = ?", id).Update("stock", quantity).Error
 }
 
-// FindByMerchantID finds all products by merchant ID
-func (r *productRepository) FindByMerchantID(merchantID uint) ([]product.Product, error) {
+// IncrementStock puts quantity units back on a product's stock
+func (r *productRepository) IncrementStock(id uint, quantity int) error {
+	return r.db.Model(&product.Product{}).Where("id = ?", id).Update("stock", gorm.Expr("stock + ?", quantity)).Error
+}
+
+// ReserveStock takes quantity units off a product's stock in a single
+// conditional update. It returns false if there was not enough stock or the
+// product is no longer for sale, including when an admin unlisted it or its
+// merchant is inactive or not verified.
+func (r *productRepository) ReserveStock(id uint, quantity int) (bool, error) {
+	result := r.db.Model(&product.Product{}).
+		Where("id = ? AND stock >= ?", id, quantity).
+		Where("is_active = ? AND expiry_date > ? AND unlisted_at IS NULL", true, time.Now()).
+		Where("merchant_id IN (SELECT id FROM merchants WHERE is_active = ? AND is_verified = ?)", true, true).
+		Update("stock", gorm.Expr("stock - ?", quantity))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FindByMerchantID finds a page of a merchant's products, newest first
+func (r *productRepository) FindByMerchantID(merchantID uint, page pagination.Page) ([]product.Product, string, error) {
+	query, err := newestFirst(r.db.Model(&product.Product{}).Where("products.merchant_id = ?", merchantID), "products", page)
+	if err != nil {
+		return nil, "", err
+	}
+
 	var products []product.Product
-	err := r.db.Where("merchant_id = ?", merchantID).Order("created_at DESC").Find(&products).Error
+	if err := withImages(query).Find(&products).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(products) > page.Limit {
+		products = products[:page.Limit]
+		last := products[len(products)-1]
+		next = pagination.TimeCursor(sortNewest, last.CreatedAt, last.ID)
+	}
+
+	return products, next, nil
+}
+
+// FindExpiringBefore finds active products expiring at or before the given time
+func (r *productRepository) FindExpiringBefore(before time.Time) ([]product.Product, error) {
+	var products []product.Product
+	err := r.db.Where("is_active = ? AND expiry_date <= ?", true, before).
+		Order("expiry_date").
+		Find(&products).Error
 	if err != nil {
 		return nil, err
 	}
 	return products, nil
 }
+
+// DeactivateExpired deactivates a product if it is still active and expired
+// at now. It returns false if the product was changed concurrently.
+func (r *productRepository) DeactivateExpired(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&product.Product{}).
+		Where("id = ? AND is_active = ? AND expiry_date <= ?", id, true, now).
+		Update("is_active", false)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateSalePrice saves the sale price and discount of a product whose sale
+// price is still from, and records the change. It returns false if the
+// price was changed concurrently.
+func (r *productRepository) UpdateSalePrice(prod *product.Product, from float64, change *product.PriceChange) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&product.Product{}).
+			Where("id = ? AND sale_price = ?", prod.ID, from).
+			Updates(map[string]interface{}{
+				"sale_price": prod.SalePrice,
+				"discount":   prod.Discount,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+		return tx.Create(change).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// FindMarkdownRules finds the markdown rules of a merchant, earliest to apply first
+func (r *productRepository) FindMarkdownRules(merchantID uint) ([]product.MarkdownRule, error) {
+	var rules []product.MarkdownRule
+	err := r.db.Where("merchant_id = ?", merchantID).
+		Order("hours_before_expiry DESC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// FindAllMarkdownRules finds the markdown rules of all merchants
+func (r *productRepository) FindAllMarkdownRules() ([]product.MarkdownRule, error) {
+	var rules []product.MarkdownRule
+	if err := r.db.Order("merchant_id, hours_before_expiry DESC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ReplaceMarkdownRules replaces all markdown rules of a merchant
+func (r *productRepository) ReplaceMarkdownRules(merchantID uint, rules []product.MarkdownRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("merchant_id = ?", merchantID).Delete(&product.MarkdownRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// RecordPriceChange records a change of a product's prices
+func (r *productRepository) RecordPriceChange(change *product.PriceChange) error {
+	return r.db.Create(change).Error
+}
+
+// FindPriceHistory finds a page of a product's price changes, newest first
+func (r *productRepository) FindPriceHistory(productID uint, page pagination.Page) ([]product.PriceChange, string, error) {
+	query, err := newestFirst(r.db.Model(&product.PriceChange{}).Where("product_price_history.product_id = ?", productID), "product_price_history", page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var changes []product.PriceChange
+	if err := query.Find(&changes).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(changes) > page.Limit {
+		changes = changes[:page.Limit]
+		last := changes[len(changes)-1]
+		next = pagination.TimeCursor(sortNewest, last.CreatedAt, last.ID)
+	}
+
+	return changes, next, nil
+}
+
+// FindImages finds the images of a product, cover first
+func (r *productRepository) FindImages(productID uint) ([]product.Image, error) {
+	var images []product.Image
+	if err := r.db.Where("product_id = ?", productID).Order("position, id").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// FindImage finds an image of a product
+func (r *productRepository) FindImage(productID uint, imageID uint) (*product.Image, error) {
+	var image product.Image
+	err := r.db.Where("id = ? AND product_id = ?", imageID, productID).First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, product.ErrImageNotFound
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateImage creates a product image
+func (r *productRepository) CreateImage(image *product.Image) error {
+	return r.db.Create(image).Error
+}
+
+// UpdateImage updates a product image
+func (r *productRepository) UpdateImage(image *product.Image) error {
+	return r.db.Save(image).Error
+}
+
+// DeleteImage deletes a product image
+func (r *productRepository) DeleteImage(imageID uint) error {
+	return r.db.Delete(&product.Image{}, imageID).Error
+}
+
+// SetImagePosition moves a product image to a position
+func (r *productRepository) SetImagePosition(imageID uint, position int) error {
+	return r.db.Model(&product.Image{}).Where("id = ?", imageID).Update("position", position).Error
+}
@@ -0,0 +1,260 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sqlmysql "github.com/go-sql-driver/mysql"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// These tests run against a real MySQL database, which they drop and
+// recreate from the migrations:
+//
+//	TEST_DATABASE_DSN='root:root@tcp(localhost:3306)/smartket_test' \
+//	    go test -tags integration ./infrastructure/database/postgres
+//
+// The database name must contain "test".
+
+func TestReserveStockConcurrent(t *testing.T) {
+	const (
+		stock  = 5
+		buyers = 50
+	)
+
+	db := integrationDatabase(t)
+	merchantID := createMerchant(t, db, true, true)
+	productID := createProduct(t, db, merchantID, stock, "")
+	repo := NewProductRepository(db)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+	)
+	start := make(chan struct{})
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			ok, err := repo.ReserveStock(productID, 1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if ok {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if reserved != stock {
+		t.Errorf("reservations = %d, want %d", reserved, stock)
+	}
+	if left := stockOf(t, db, productID); left != 0 {
+		t.Errorf("stock left = %d, want 0", left)
+	}
+}
+
+func TestReserveStockRollsBackWithTransaction(t *testing.T) {
+	db := integrationDatabase(t)
+	merchantID := createMerchant(t, db, true, true)
+	first := createProduct(t, db, merchantID, 5, "")
+	second := createProduct(t, db, merchantID, 1, "")
+
+	errOutOfStock := errors.New("out of stock")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		repo := NewProductRepository(db).WithTrx(tx)
+		for _, item := range []struct {
+			id       uint
+			quantity int
+		}{{first, 2}, {second, 3}} {
+			ok, err := repo.ReserveStock(item.id, item.quantity)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errOutOfStock
+			}
+		}
+		return nil
+	})
+	if !errors.Is(err, errOutOfStock) {
+		t.Fatalf("err = %v, want %v", err, errOutOfStock)
+	}
+
+	if stock := stockOf(t, db, first); stock != 5 {
+		t.Errorf("stock of first product = %d, want 5", stock)
+	}
+	if stock := stockOf(t, db, second); stock != 1 {
+		t.Errorf("stock of second product = %d, want 1", stock)
+	}
+}
+
+func TestReserveStockSkipsProductsNotForSale(t *testing.T) {
+	db := integrationDatabase(t)
+	verified := createMerchant(t, db, true, true)
+	unverified := createMerchant(t, db, true, false)
+	inactiveShop := createMerchant(t, db, false, true)
+	repo := NewProductRepository(db)
+
+	tests := []struct {
+		name      string
+		productID uint
+	}{
+		{"inactive", createProduct(t, db, verified, 5, "is_active = FALSE")},
+		{"expired", createProduct(t, db, verified, 5, "expiry_date = NOW() - INTERVAL 1 HOUR")},
+		{"unlisted", createProduct(t, db, verified, 5, "unlisted_at = NOW()")},
+		{"unverified merchant", createProduct(t, db, unverified, 5, "")},
+		{"inactive merchant", createProduct(t, db, inactiveShop, 5, "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := repo.ReserveStock(tt.productID, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok {
+				t.Error("reserved stock of a product not for sale")
+			}
+			if stock := stockOf(t, db, tt.productID); stock != 5 {
+				t.Errorf("stock = %d, want 5", stock)
+			}
+		})
+	}
+}
+
+// integrationDatabase recreates the test database from the migrations
+func integrationDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	cfg, err := sqlmysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := cfg.DBName
+	if !strings.Contains(name, "test") {
+		t.Fatalf("refusing to drop database %q, its name must contain \"test\"", name)
+	}
+	cfg.ParseTime = true
+	cfg.MultiStatements = true
+
+	cfg.DBName = ""
+	server := open(t, cfg.FormatDSN())
+	if err := server.Exec("DROP DATABASE IF EXISTS `" + name + "`").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Exec("CREATE DATABASE `" + name + "` CHARACTER SET utf8mb4").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.DBName = name
+	db := open(t, cfg.FormatDSN())
+
+	files, err := filepath.Glob("../../../migration/20241111*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up := strings.SplitN(string(data), "-- +migrate Down", 2)[0]
+		if err := db.Exec(up).Error; err != nil {
+			t.Fatalf("migrating %s: %v", filepath.Base(file), err)
+		}
+	}
+
+	return db
+}
+
+func open(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// createMerchant creates a shop with its owner
+func createMerchant(t *testing.T, db *gorm.DB, active bool, verified bool) uint {
+	t.Helper()
+
+	email := "owner-" + time.Now().Format("150405.000000000") + "@example.com"
+	if err := db.Exec("INSERT INTO users (email, password, name, role) VALUES (?, 'x', 'Owner', 'merchant')", email).Error; err != nil {
+		t.Fatal(err)
+	}
+	var userID uint
+	db.Raw("SELECT id FROM users WHERE email = ?", email).Scan(&userID)
+
+	err := db.Exec("INSERT INTO merchants (user_id, shop_name, shop_address, phone, is_active, is_verified) VALUES (?, 'Shop', 'Q1', '0900000000', ?, ?)",
+		userID, active, verified).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	var merchantID uint
+	db.Raw("SELECT id FROM merchants WHERE user_id = ?", userID).Scan(&merchantID)
+	return merchantID
+}
+
+// createProduct creates a product on sale for a day, then applies the
+// optional SET clause
+func createProduct(t *testing.T, db *gorm.DB, merchantID uint, stock int, set string) uint {
+	t.Helper()
+
+	prod := product.Product{
+		MerchantID: merchantID,
+		Name:       "Bread bag",
+		Category:   "khac",
+		OrigPrice:  40000,
+		SalePrice:  20000,
+		Stock:      stock,
+		ExpiryDate: time.Now().Add(24 * time.Hour),
+		IsActive:   true,
+	}
+	if err := db.Omit("Images").Create(&prod).Error; err != nil {
+		t.Fatal(err)
+	}
+	if set != "" {
+		if err := db.Exec("UPDATE products SET "+set+" WHERE id = ?", prod.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return prod.ID
+}
+
+func stockOf(t *testing.T, db *gorm.DB, id uint) int {
+	t.Helper()
+	var stock int
+	if err := db.Raw("SELECT stock FROM products WHERE id = ?", id).Scan(&stock).Error; err != nil {
+		t.Fatal(err)
+	}
+	return stock
+}
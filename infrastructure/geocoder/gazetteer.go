@@ -0,0 +1,153 @@
+package geocoder
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/geocoding"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+)
+
+// defaultGazetteer lists the districts of Ho Chi Minh City
+//
+//go:embed hcmc_districts.json
+var defaultGazetteer []byte
+
+type gazetteerPlace struct {
+	Name      string   `json:"name"`
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Aliases   []string `json:"aliases"`
+}
+
+type gazetteerFile struct {
+	Places   []gazetteerPlace `json:"places"`
+	Fallback *gazetteerPlace  `json:"fallback"`
+}
+
+// Gazetteer geocodes addresses offline against a list of known places.
+// The fallback place is only returned when no other place matches.
+type Gazetteer struct {
+	places   []gazetteerPlace
+	fallback *gazetteerPlace
+}
+
+// NewGazetteer loads a gazetteer from a JSON file, or the built-in HCMC
+// districts when path is empty
+func NewGazetteer(path string) (*Gazetteer, error) {
+	data := defaultGazetteer
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var file gazetteerFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	g := &Gazetteer{places: file.Places, fallback: file.Fallback}
+	for i := range g.places {
+		g.places[i].Aliases = normalizeAliases(g.places[i])
+	}
+	if g.fallback != nil {
+		g.fallback.Aliases = normalizeAliases(*g.fallback)
+	}
+
+	return g, nil
+}
+
+// gazetteerMatch is a place found in a query. Position is where the alias
+// starts in the query, or -1 for a partial match on what the user typed.
+type gazetteerMatch struct {
+	place    *gazetteerPlace
+	position int
+	length   int
+}
+
+// Geocode finds the places named in the query. Vietnamese addresses go from
+// street to city, so the place mentioned last ranks first.
+func (g *Gazetteer) Geocode(query string, limit int) ([]geocoding.Result, error) {
+	normalized := normalizePlace(query)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	matches := matchPlaces(g.places, normalized)
+	if len(matches) == 0 && g.fallback != nil {
+		matches = matchPlaces([]gazetteerPlace{*g.fallback}, normalized)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].position != matches[j].position {
+			return matches[i].position > matches[j].position
+		}
+		return matches[i].length > matches[j].length
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	results := make([]geocoding.Result, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, geocoding.Result{
+			Address:   m.place.Name,
+			Latitude:  m.place.Latitude,
+			Longitude: m.place.Longitude,
+			Source:    "gazetteer",
+		})
+	}
+
+	return results, nil
+}
+
+// matchPlaces finds the best alias match of each place in the normalized query
+func matchPlaces(places []gazetteerPlace, normalized string) []gazetteerMatch {
+	padded := " " + normalized + " "
+
+	var matches []gazetteerMatch
+	for i := range places {
+		best := gazetteerMatch{place: &places[i], position: -2}
+		for _, alias := range places[i].Aliases {
+			candidate := gazetteerMatch{place: &places[i], position: -2, length: len(alias)}
+			if pos := strings.LastIndex(padded, " "+alias+" "); pos >= 0 {
+				candidate.position = pos
+			} else if len(normalized) >= 3 && strings.Contains(" "+alias, " "+normalized) {
+				// The query is the start of a place name, e.g. while typing
+				candidate.position = -1
+			}
+
+			if candidate.position > best.position ||
+				(candidate.position == best.position && candidate.length > best.length) {
+				best = candidate
+			}
+		}
+		if best.position > -2 {
+			matches = append(matches, best)
+		}
+	}
+
+	return matches
+}
+
+// normalizeAliases returns the folded aliases of a place, including its name
+func normalizeAliases(place gazetteerPlace) []string {
+	aliases := []string{normalizePlace(place.Name)}
+	for _, alias := range place.Aliases {
+		if normalized := normalizePlace(alias); normalized != "" {
+			aliases = append(aliases, normalized)
+		}
+	}
+	return aliases
+}
+
+// normalizePlace folds diacritics and reduces text to space separated words
+func normalizePlace(s string) string {
+	return strings.Join(utils.Tokenize(s), " ")
+}
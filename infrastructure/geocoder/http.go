@@ -0,0 +1,91 @@
+package geocoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/geocoding"
+)
+
+// httpTimeout bounds a single request to the geocoding API
+const httpTimeout = 5 * time.Second
+
+// HTTPGeocoder geocodes addresses with a Nominatim-compatible search API.
+// Pointing GEOCODER_URL at a local stub replaces the real service.
+type HTTPGeocoder struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPGeocoder creates a geocoder for the given search endpoint
+func NewHTTPGeocoder(endpoint string) *HTTPGeocoder {
+	return &HTTPGeocoder{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: httpTimeout},
+	}
+}
+
+type nominatimPlace struct {
+	DisplayName string `json:"display_name"`
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+}
+
+// Geocode queries the search API for the address
+func (g *HTTPGeocoder) Geocode(query string, limit int) ([]geocoding.Result, error) {
+	u, err := url.Parse(g.endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	params := u.Query()
+	params.Set("q", query)
+	params.Set("format", "json")
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "smartket-geocoder")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding request failed with status %d", resp.StatusCode)
+	}
+
+	var places []nominatimPlace
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return nil, fmt.Errorf("decoding geocoding response: %w", err)
+	}
+
+	results := make([]geocoding.Result, 0, len(places))
+	for _, place := range places {
+		lat, latErr := strconv.ParseFloat(place.Lat, 64)
+		lng, lngErr := strconv.ParseFloat(place.Lon, 64)
+		if latErr != nil || lngErr != nil {
+			continue
+		}
+		results = append(results, geocoding.Result{
+			Address:   place.DisplayName,
+			Latitude:  lat,
+			Longitude: lng,
+			Source:    "http",
+		})
+	}
+
+	return results, nil
+}
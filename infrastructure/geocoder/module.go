@@ -0,0 +1,34 @@
+package geocoder
+
+import (
+	"strings"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/geocoding"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"go.uber.org/fx"
+)
+
+// Module exports the configured geocoding provider
+var Module = fx.Options(
+	fx.Provide(NewProvider),
+)
+
+// NewProvider selects the geocoding adapter set by GEOCODER_PROVIDER
+func NewProvider(env lib.Env, logger lib.Logger) geocoding.Provider {
+	switch strings.ToLower(env.GeocoderProvider) {
+	case "", "gazetteer":
+		gazetteer, err := NewGazetteer(env.GeocoderGazetteerFile)
+		if err != nil {
+			logger.Panic("cannot load geocoding gazetteer: ", err)
+		}
+		return gazetteer
+	case "http":
+		if env.GeocoderURL == "" {
+			logger.Panic("GEOCODER_URL is required for the http geocoder")
+		}
+		return NewHTTPGeocoder(env.GeocoderURL)
+	default:
+		logger.Panicf("unsupported GEOCODER_PROVIDER %q", env.GeocoderProvider)
+		return nil
+	}
+}
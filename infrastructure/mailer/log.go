@@ -0,0 +1,24 @@
+package mailer
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/mail"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+)
+
+// LogSender writes emails to the log instead of delivering them. It is
+// meant for development, where the links in the emails are read from the
+// log.
+type LogSender struct {
+	logger lib.Logger
+}
+
+// NewLogSender creates a sender writing to the logger
+func NewLogSender(logger lib.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Send logs the email
+func (s *LogSender) Send(message mail.Message) error {
+	s.logger.Info("email to ", message.To, ": ", message.Subject, "\n", message.Body)
+	return nil
+}
@@ -0,0 +1,30 @@
+package mailer
+
+import (
+	"strings"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/mail"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"go.uber.org/fx"
+)
+
+// Module exports the configured mail sender
+var Module = fx.Options(
+	fx.Provide(NewSender),
+)
+
+// NewSender selects the mail adapter set by MAIL_PROVIDER
+func NewSender(env lib.Env, logger lib.Logger) mail.Sender {
+	switch strings.ToLower(env.MailProvider) {
+	case "", "log":
+		return NewLogSender(logger)
+	case "smtp":
+		if env.MailSMTPAddr == "" || env.MailFrom == "" {
+			logger.Panic("MAIL_SMTP_ADDR and MAIL_FROM are required for the smtp mailer")
+		}
+		return NewSMTPSender(env.MailSMTPAddr, env.MailSMTPUsername, env.MailSMTPPassword, env.MailFrom)
+	default:
+		logger.Panicf("unsupported MAIL_PROVIDER %q", env.MailProvider)
+		return nil
+	}
+}
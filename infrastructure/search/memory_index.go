@@ -0,0 +1,243 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+)
+
+// Field weights: a word in the name counts more than one in the description
+const (
+	nameWeight        = 3.0
+	categoryWeight    = 2.0
+	descriptionWeight = 1.0
+)
+
+// prefixWeight discounts words only matched by the prefix of the last query
+// word, so results keep up while the user is still typing
+const prefixWeight = 0.5
+
+// saturation limits how much repeating a word raises the score (BM25 k1)
+const saturation = 1.2
+
+// refreshInterval is how long a loaded index is used before it is reloaded
+const refreshInterval = 10 * time.Minute
+
+// MemoryIndex is an in-process inverted index over active products. It is
+// loaded from the database on the first search and updated on every product
+// write, so each app instance holds its own copy. Writes made by other
+// processes, like the product:expire command, are picked up by reloading
+// every refreshInterval.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	loadedAt time.Time
+	load     func() ([]product.Product, error)
+	postings map[string]map[uint]float64 // word -> product -> weighted frequency
+	docs     map[uint][]string           // product -> its distinct words
+}
+
+// NewMemoryIndex creates an index loading products from the repository
+func NewMemoryIndex(repo product.Repository) *MemoryIndex {
+	return &MemoryIndex{
+		load:     repo.FindActive,
+		postings: map[string]map[uint]float64{},
+		docs:     map[uint][]string{},
+	}
+}
+
+// Index adds or replaces a product. Inactive and expired products are removed.
+func (x *MemoryIndex) Index(prod *product.Product) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	// Writes before the first load are picked up by the load itself
+	if x.loadedAt.IsZero() {
+		return nil
+	}
+
+	x.add(prod)
+	return nil
+}
+
+// Remove drops a product from the index
+func (x *MemoryIndex) Remove(id uint) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(id)
+	return nil
+}
+
+// Search returns products matching the query by relevance. Products matching
+// every query word rank first; if none do, partial matches are returned.
+func (x *MemoryIndex) Search(query string, limit int) ([]product.SearchHit, error) {
+	words := uniqueWords(utils.Tokenize(query))
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	if err := x.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	total := float64(len(x.docs))
+	scores := map[uint]float64{}
+	matched := map[uint]int{}
+
+	for i, word := range words {
+		terms := map[string]float64{word: 1}
+		if i == len(words)-1 {
+			for term := range x.postings {
+				if term != word && strings.HasPrefix(term, word) {
+					terms[term] = prefixWeight
+				}
+			}
+		}
+
+		hits := map[uint]bool{}
+		for term, weight := range terms {
+			postings := x.postings[term]
+			if len(postings) == 0 {
+				continue
+			}
+			idf := math.Log(1 + total/float64(len(postings)))
+			for id, freq := range postings {
+				scores[id] += weight * idf * freq * (saturation + 1) / (freq + saturation)
+				hits[id] = true
+			}
+		}
+		for id := range hits {
+			matched[id]++
+		}
+	}
+
+	allWords := false
+	for _, n := range matched {
+		if n == len(words) {
+			allWords = true
+			break
+		}
+	}
+
+	results := make([]product.SearchHit, 0, len(scores))
+	for id, score := range scores {
+		if allWords && matched[id] < len(words) {
+			continue
+		}
+		results = append(results, product.SearchHit{ProductID: id, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if matched[a.ProductID] != matched[b.ProductID] {
+			return matched[a.ProductID] > matched[b.ProductID]
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.ProductID > b.ProductID
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// ensureLoaded fills the index from the database, again once it is older
+// than refreshInterval
+func (x *MemoryIndex) ensureLoaded() error {
+	x.mu.RLock()
+	fresh := x.fresh(time.Now())
+	x.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.fresh(time.Now()) {
+		return nil
+	}
+
+	products, err := x.load()
+	if err != nil {
+		return err
+	}
+	x.postings = map[string]map[uint]float64{}
+	x.docs = map[uint][]string{}
+	for i := range products {
+		x.add(&products[i])
+	}
+	x.loadedAt = time.Now()
+
+	return nil
+}
+
+// fresh reports whether the index was loaded recently enough. Callers hold
+// the lock.
+func (x *MemoryIndex) fresh(now time.Time) bool {
+	return !x.loadedAt.IsZero() && now.Sub(x.loadedAt) < refreshInterval
+}
+
+// add indexes a product, replacing any previous version. Callers hold the lock.
+func (x *MemoryIndex) add(prod *product.Product) {
+	x.remove(prod.ID)
+	if !prod.IsActive || prod.IsExpired(time.Now()) {
+		return
+	}
+
+	freqs := map[string]float64{}
+	for _, word := range utils.Tokenize(prod.Name) {
+		freqs[word] += nameWeight
+	}
+	for _, word := range utils.Tokenize(prod.Category) {
+		freqs[word] += categoryWeight
+	}
+	for _, word := range utils.Tokenize(prod.Description) {
+		freqs[word] += descriptionWeight
+	}
+
+	words := make([]string, 0, len(freqs))
+	for word, freq := range freqs {
+		if x.postings[word] == nil {
+			x.postings[word] = map[uint]float64{}
+		}
+		x.postings[word][prod.ID] = freq
+		words = append(words, word)
+	}
+	x.docs[prod.ID] = words
+}
+
+// remove drops a product from the postings. Callers hold the lock.
+func (x *MemoryIndex) remove(id uint) {
+	for _, word := range x.docs[id] {
+		delete(x.postings[word], id)
+		if len(x.postings[word]) == 0 {
+			delete(x.postings, word)
+		}
+	}
+	delete(x.docs, id)
+}
+
+// uniqueWords drops repeated words keeping their first position
+func uniqueWords(words []string) []string {
+	seen := map[string]bool{}
+	unique := words[:0]
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			unique = append(unique, word)
+		}
+	}
+	return unique
+}
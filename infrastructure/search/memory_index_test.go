@@ -0,0 +1,129 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+)
+
+func TestMemoryIndexReloadsStaleIndex(t *testing.T) {
+	products := []product.Product{
+		{ID: 1, Name: "Bread bag", IsActive: true},
+		{ID: 2, Name: "Bread rolls", IsActive: true},
+	}
+	index := &MemoryIndex{
+		load: func() ([]product.Product, error) { return products, nil },
+	}
+
+	hits, err := index.Search("bread", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("hits = %d, want 2", len(hits))
+	}
+
+	// Another process deactivates product 2
+	products = products[:1]
+
+	if hits, _ = index.Search("bread", 0); len(hits) != 2 {
+		t.Errorf("fresh index: hits = %d, want 2", len(hits))
+	}
+
+	index.loadedAt = time.Now().Add(-refreshInterval)
+	if hits, _ = index.Search("bread", 0); len(hits) != 1 || hits[0].ProductID != 1 {
+		t.Errorf("stale index: hits = %+v, want product 1 only", hits)
+	}
+}
+
+// loadedIndex returns an index already holding the given products
+func loadedIndex(t *testing.T, products ...product.Product) *MemoryIndex {
+	t.Helper()
+	index := &MemoryIndex{
+		load: func() ([]product.Product, error) { return products, nil },
+	}
+	if err := index.ensureLoaded(); err != nil {
+		t.Fatal(err)
+	}
+	return index
+}
+
+func TestMemoryIndexFoldsDiacritics(t *testing.T) {
+	index := loadedIndex(t,
+		product.Product{ID: 1, Name: "Mì Hảo Hảo tôm chua cay", IsActive: true},
+		product.Product{ID: 2, Name: "Bánh mì Đà Lạt", IsActive: true},
+	)
+
+	hits, err := index.Search("mi hao hao", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].ProductID != 1 {
+		t.Errorf("hits = %+v, want product 1 only", hits)
+	}
+
+	if hits, _ = index.Search("BÁNH MÌ da lat", 0); len(hits) != 1 || hits[0].ProductID != 2 {
+		t.Errorf("hits = %+v, want product 2 only", hits)
+	}
+}
+
+func TestMemoryIndexRanksNameAboveDescription(t *testing.T) {
+	index := loadedIndex(t,
+		product.Product{ID: 1, Name: "Combo bữa sáng", Description: "Gồm sữa chua và bánh mì", IsActive: true},
+		product.Product{ID: 2, Name: "Sữa chua nếp cẩm", Description: "Hũ 100g", IsActive: true},
+	)
+
+	hits, err := index.Search("sua chua", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("hits = %d, want 2", len(hits))
+	}
+	if hits[0].ProductID != 2 || hits[0].Score <= hits[1].Score {
+		t.Errorf("hits = %+v, want the name match first", hits)
+	}
+}
+
+func TestMemoryIndexIndexAndRemove(t *testing.T) {
+	index := loadedIndex(t,
+		product.Product{ID: 1, Name: "Bread bag", IsActive: true},
+	)
+
+	// Renaming a product replaces its words
+	if err := index.Index(&product.Product{ID: 1, Name: "Sushi box", IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+	if hits, _ := index.Search("bread", 0); len(hits) != 0 {
+		t.Errorf("old name: hits = %+v, want none", hits)
+	}
+	if hits, _ := index.Search("sushi", 0); len(hits) != 1 || hits[0].ProductID != 1 {
+		t.Errorf("new name: hits = %+v, want product 1", hits)
+	}
+
+	// Deactivated products leave the index
+	if err := index.Index(&product.Product{ID: 1, Name: "Sushi box"}); err != nil {
+		t.Fatal(err)
+	}
+	if hits, _ := index.Search("sushi", 0); len(hits) != 0 {
+		t.Errorf("inactive: hits = %+v, want none", hits)
+	}
+
+	if err := index.Index(&product.Product{ID: 2, Name: "Sushi rolls", IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+	if hits, _ := index.Search("sushi", 0); len(hits) != 1 || hits[0].ProductID != 2 {
+		t.Errorf("added: hits = %+v, want product 2", hits)
+	}
+
+	if err := index.Remove(2); err != nil {
+		t.Fatal(err)
+	}
+	if hits, _ := index.Search("sushi", 0); len(hits) != 0 {
+		t.Errorf("removed: hits = %+v, want none", hits)
+	}
+	if len(index.postings) != 0 || len(index.docs) != 0 {
+		t.Errorf("postings = %v, docs = %v, want both empty", index.postings, index.docs)
+	}
+}
@@ -0,0 +1,16 @@
+package search
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"go.uber.org/fx"
+)
+
+// Module exports the product search backend
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			NewMemoryIndex,
+			fx.As(new(product.SearchIndex)),
+		),
+	),
+)
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/media"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"go.uber.org/fx"
+)
+
+// Default settings of the local storage
+const (
+	DefaultLocalDir  = "./uploads"
+	DefaultPublicURL = "/media"
+)
+
+// Module exports the configured file storage
+var Module = fx.Options(
+	fx.Provide(NewStorage),
+)
+
+// NewStorage selects the storage adapter set by STORAGE_PROVIDER
+func NewStorage(env lib.Env, logger lib.Logger) media.Storage {
+	switch strings.ToLower(env.StorageProvider) {
+	case "", "local":
+		dir, publicURL := LocalSettings(env)
+		return NewLocalStorage(dir, publicURL)
+	case "s3":
+		storage, err := NewS3Storage(S3Config{
+			Endpoint:      env.StorageS3Endpoint,
+			Region:        env.StorageS3Region,
+			Bucket:        env.StorageS3Bucket,
+			AccessKey:     env.StorageS3AccessKey,
+			SecretKey:     env.StorageS3SecretKey,
+			PublicURL:     env.StoragePublicURL,
+			PrivateBucket: env.StorageS3PrivateBucket,
+		})
+		if err != nil {
+			logger.Panic("cannot configure s3 storage: ", err)
+		}
+		return storage
+	default:
+		logger.Panicf("unsupported STORAGE_PROVIDER %q", env.StorageProvider)
+		return nil
+	}
+}
+
+// LocalSettings gives the directory and public URL of the local storage
+func LocalSettings(env lib.Env) (string, string) {
+	dir, publicURL := env.StorageLocalDir, env.StoragePublicURL
+	if dir == "" {
+		dir = DefaultLocalDir
+	}
+	if publicURL == "" {
+		publicURL = DefaultPublicURL
+	}
+	return dir, publicURL
+}
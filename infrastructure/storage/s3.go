@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/media"
+)
+
+// s3Timeout bounds a single request to the object store
+const s3Timeout = 30 * time.Second
+
+// maxObjectBytes caps the objects read back from the store
+const maxObjectBytes = 32 << 20
+
+// S3Storage keeps files in a bucket of an S3-compatible object store. It
+// uses path-style requests, so MinIO or any other local stand-in works by
+// pointing the endpoint at it. Private files go to a bucket of their own,
+// so a public read policy on the main bucket never exposes them.
+type S3Storage struct {
+	client        *minio.Client
+	bucket        string
+	privateBucket string
+	publicURL     string
+}
+
+// S3Config configures an S3Storage
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL serves the files, e.g. a CDN. It defaults to the bucket URL.
+	PublicURL string
+	// PrivateBucket keeps the files under media.PrivatePrefix. It must not
+	// be readable publicly.
+	PrivateBucket string
+}
+
+// NewS3Storage creates a storage for the configured bucket
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" || endpoint.Path != "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if config.PrivateBucket == "" || config.PrivateBucket == config.Bucket {
+		return nil, fmt.Errorf("s3 private bucket is required and must differ from the bucket")
+	}
+
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	publicURL := strings.TrimRight(config.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = endpoint.String() + "/" + config.Bucket
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure:       endpoint.Scheme == "https",
+		Region:       region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Storage{
+		client:        client,
+		bucket:        config.Bucket,
+		privateBucket: config.PrivateBucket,
+		publicURL:     publicURL,
+	}, nil
+}
+
+// Put uploads the object, replacing any object with the same key
+func (s *S3Storage) Put(key string, data []byte, contentType string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s3Timeout)
+	defer cancel()
+
+	_, err := s.client.PutObject(ctx, s.bucketFor(key), key, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Get downloads the object
+func (s *S3Storage) Get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s3Timeout)
+	defer cancel()
+
+	object, err := s.client.GetObject(ctx, s.bucketFor(key), key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	// The request is only sent on the first read
+	data, err := io.ReadAll(io.LimitReader(object, maxObjectBytes))
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, media.ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Delete removes the object. S3 reports success for missing objects.
+func (s *S3Storage) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s3Timeout)
+	defer cancel()
+
+	return s.client.RemoveObject(ctx, s.bucketFor(key), key, minio.RemoveObjectOptions{})
+}
+
+// URL gives the public URL of the object. Private objects have none.
+func (s *S3Storage) URL(key string) string {
+	if strings.HasPrefix(key, media.PrivatePrefix) {
+		return ""
+	}
+	return s.publicURL + "/" + escapePath(key)
+}
+
+// bucketFor gives the bucket holding the object
+func (s *S3Storage) bucketFor(key string) string {
+	if strings.HasPrefix(key, media.PrivatePrefix) {
+		return s.privateBucket
+	}
+	return s.bucket
+}
+
+// escapePath percent-encodes a key for a URL, keeping its slashes
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
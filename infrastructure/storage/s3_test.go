@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/media"
+)
+
+func TestS3StorageKeepsPrivateFilesInPrivateBucket(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	storage, err := NewS3Storage(S3Config{
+		Endpoint:      server.URL,
+		Bucket:        "smartket",
+		PrivateBucket: "smartket-private",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := storage.Put("products/1/a.jpg", []byte("image"), "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put("private/kyc/1/b.pdf", []byte("licence"), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/smartket/products/1/a.jpg", "/smartket-private/private/kyc/1/b.pdf"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	if url := storage.URL("private/kyc/1/b.pdf"); url != "" {
+		t.Errorf("private URL = %q, want none", url)
+	}
+}
+
+func TestNewS3StorageRequiresSeparatePrivateBucket(t *testing.T) {
+	for _, private := range []string{"", "smartket"} {
+		_, err := NewS3Storage(S3Config{
+			Endpoint:      "http://localhost:9000",
+			Bucket:        "smartket",
+			PrivateBucket: private,
+		})
+		if err == nil {
+			t.Errorf("private bucket %q was accepted", private)
+		}
+	}
+}
+
+func TestS3StorageGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			t.Errorf("%s %s is not signed", r.Method, r.URL.Path)
+		}
+		if r.URL.Path != "/smartket/products/1/a.jpg" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("ETag", `"1"`)
+		w.Header().Set("Last-Modified", "Mon, 2 Jan 2006 15:04:05 GMT")
+		w.Write([]byte("image"))
+	}))
+	defer server.Close()
+
+	storage, err := NewS3Storage(S3Config{
+		Endpoint:      server.URL,
+		Bucket:        "smartket",
+		PrivateBucket: "smartket-private",
+		AccessKey:     "minio",
+		SecretKey:     "minio-secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := storage.Get("products/1/a.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "image" {
+		t.Errorf("data = %q, want %q", data, "image")
+	}
+
+	if _, err := storage.Get("products/1/missing.jpg"); !errors.Is(err, media.ErrFileNotFound) {
+		t.Errorf("missing object: err = %v, want %v", err, media.ErrFileNotFound)
+	}
+}
@@ -0,0 +1,124 @@
+package apperror
+
+import "errors"
+
+// Kind classifies an error so it can be mapped to a transport status
+type Kind string
+
+const (
+	KindBadRequest        Kind = "bad_request"
+	KindUnauthorized      Kind = "unauthorized"
+	KindForbidden         Kind = "forbidden"
+	KindNotFound          Kind = "not_found"
+	KindConflict          Kind = "conflict"
+	KindValidation        Kind = "validation"
+	KindInsufficientStock Kind = "insufficient_stock"
+	KindTooManyRequests   Kind = "too_many_requests"
+	KindInternal          Kind = "internal"
+)
+
+// Error is an application error with a kind and a machine-readable code
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error
+}
+
+// New creates a new application error
+func New(kind Kind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// BadRequest creates an error for malformed input
+func BadRequest(code string, message string) *Error {
+	return New(KindBadRequest, code, message)
+}
+
+// Unauthorized creates an error for missing or invalid credentials
+func Unauthorized(code string, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+// Forbidden creates an error for actions the caller may not perform
+func Forbidden(code string, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+// NotFound creates an error for missing resources
+func NotFound(code string, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+// Conflict creates an error for requests that clash with the current state
+func Conflict(code string, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+// Validation creates an error for well-formed input that breaks a business rule
+func Validation(code string, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+// InsufficientStock creates an error for orders exceeding available stock
+func InsufficientStock(code string, message string) *Error {
+	return New(KindInsufficientStock, code, message)
+}
+
+// TooManyRequests creates an error for callers over their rate limit
+func TooManyRequests(code string, message string) *Error {
+	return New(KindTooManyRequests, code, message)
+}
+
+// Internal wraps an unexpected error
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: "internal server error", Err: err}
+}
+
+// Error returns the error message
+func (e *Error) Error() string {
+	if e.Err != nil && e.Kind == KindInternal {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors with the same code, so a copy with a different message
+// still matches its sentinel
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithMessage returns a copy of the error with a different message
+func (e *Error) WithMessage(message string) *Error {
+	copied := *e
+	copied.Message = message
+	return &copied
+}
+
+// Wrap returns a copy of the error wrapping the given cause
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// From returns err as an application error, treating unknown errors as internal
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
+
+// KindOf returns the kind of err
+func KindOf(err error) Kind {
+	return From(err).Kind
+}
@@ -18,6 +18,37 @@ type Env struct {
 	DBPort      string `mapstructure:"DB_PORT"`
 	DBName      string `mapstructure:"DB_NAME"`
 	JWTSecret   string `mapstructure:"JWT_SECRET"`
+
+	JWTAlgorithm      string `mapstructure:"JWT_ALGORITHM"`
+	JWTPrivateKeyFile string `mapstructure:"JWT_PRIVATE_KEY_FILE"`
+	JWTKeyID          string `mapstructure:"JWT_KEY_ID"`
+	JWTVerifyKeys     string `mapstructure:"JWT_VERIFY_KEYS"`
+	JWTIssuer         string `mapstructure:"JWT_ISSUER"`
+	JWTAudience       string `mapstructure:"JWT_AUDIENCE"`
+
+	RedeemTokenSecret string `mapstructure:"REDEEM_TOKEN_SECRET"`
+
+	GeocoderProvider      string `mapstructure:"GEOCODER_PROVIDER"`
+	GeocoderGazetteerFile string `mapstructure:"GEOCODER_GAZETTEER_FILE"`
+	GeocoderURL           string `mapstructure:"GEOCODER_URL"`
+
+	StorageProvider    string `mapstructure:"STORAGE_PROVIDER"`
+	StorageLocalDir    string `mapstructure:"STORAGE_LOCAL_DIR"`
+	StoragePublicURL   string `mapstructure:"STORAGE_PUBLIC_URL"`
+	StorageS3Endpoint  string `mapstructure:"STORAGE_S3_ENDPOINT"`
+	StorageS3Region    string `mapstructure:"STORAGE_S3_REGION"`
+	StorageS3Bucket    string `mapstructure:"STORAGE_S3_BUCKET"`
+	StorageS3AccessKey string `mapstructure:"STORAGE_S3_ACCESS_KEY"`
+	StorageS3SecretKey string `mapstructure:"STORAGE_S3_SECRET_KEY"`
+
+	StorageS3PrivateBucket string `mapstructure:"STORAGE_S3_PRIVATE_BUCKET"`
+
+	AppURL           string `mapstructure:"APP_URL"`
+	MailProvider     string `mapstructure:"MAIL_PROVIDER"`
+	MailFrom         string `mapstructure:"MAIL_FROM"`
+	MailSMTPAddr     string `mapstructure:"MAIL_SMTP_ADDR"`
+	MailSMTPUsername string `mapstructure:"MAIL_SMTP_USERNAME"`
+	MailSMTPPassword string `mapstructure:"MAIL_SMTP_PASSWORD"`
 }
 
 // NewEnv creates a new environment
@@ -0,0 +1,108 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	"golang.org/x/image/draw"
+
+	// Register the decoders of the accepted upload formats
+	_ "image/gif"
+	_ "image/png"
+)
+
+// MaxPixels caps the size of decoded images so a small compressed upload
+// cannot expand into a huge bitmap
+const MaxPixels = 40_000_000
+
+// thumbnailQuality is the JPEG quality of generated thumbnails
+const thumbnailQuality = 80
+
+// Errors returned while reading images
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooManyPixels     = errors.New("image has too many pixels")
+)
+
+// Info describes an encoded image
+type Info struct {
+	Format      string // jpeg, png or gif
+	ContentType string
+	Width       int
+	Height      int
+}
+
+var contentTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+}
+
+// Inspect reads the format and dimensions of an encoded image without
+// decoding its pixels
+func Inspect(data []byte) (*Info, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrUnsupportedFormat
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	return &Info{
+		Format:      format,
+		ContentType: contentType,
+		Width:       config.Width,
+		Height:      config.Height,
+	}, nil
+}
+
+// Thumbnail decodes an image and encodes a JPEG copy fitting in a
+// maxSize square. Smaller images keep their size. Transparent areas are
+// flattened onto white.
+func Thumbnail(data []byte, maxSize int) ([]byte, error) {
+	if _, err := Inspect(data); err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	bounds := src.Bounds()
+	width, height := fit(bounds.Dx(), bounds.Dy(), maxSize)
+
+	thumb := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(thumb, thumb.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), src, bounds, draw.Over, nil)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, thumb, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// fit scales width and height down to fit in a maxSize square, keeping the
+// aspect ratio
+func fit(width int, height int, maxSize int) (int, int) {
+	if width <= maxSize && height <= maxSize {
+		return width, height
+	}
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
@@ -0,0 +1,49 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestThumbnail(t *testing.T) {
+	// A transparent 400x200 PNG with an opaque red left half
+	src := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			src.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var data bytes.Buffer
+	if err := png.Encode(&data, src); err != nil {
+		t.Fatal(err)
+	}
+
+	thumb, err := Thumbnail(data.Bytes(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if size := img.Bounds().Size(); size != image.Pt(100, 50) {
+		t.Fatalf("size = %v, want 100x50", size)
+	}
+	if r, g, b, _ := img.At(10, 25).RGBA(); r>>8 < 230 || g>>8 > 30 || b>>8 > 30 {
+		t.Errorf("left pixel = %d,%d,%d, want red", r>>8, g>>8, b>>8)
+	}
+	if r, g, b, _ := img.At(90, 25).RGBA(); r>>8 < 230 || g>>8 < 230 || b>>8 < 230 {
+		t.Errorf("right pixel = %d,%d,%d, want white", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestThumbnailRejectsOtherFormats(t *testing.T) {
+	if _, err := Thumbnail([]byte("%PDF-1.4"), 100); err != ErrUnsupportedFormat {
+		t.Errorf("err = %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
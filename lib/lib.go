@@ -11,6 +11,8 @@ var Module = fx.Options(
 	fx.Provide(NewEnv),
 	fx.Provide(GetLogger),
 	fx.Provide(NewDatabase),
+	fx.Provide(NewTokenManager),
+	fx.Provide(NewRedeemTokenManager),
 	fx.Provide(func(db Database) *gorm.DB {
 		return db.DB
 	}),
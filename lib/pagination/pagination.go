@@ -0,0 +1,98 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+)
+
+// Page sizes
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ErrInvalidCursor is returned for cursors that cannot be decoded or belong
+// to a different sort order
+var ErrInvalidCursor = apperror.BadRequest("invalid_cursor", "invalid pagination cursor")
+
+// Cursor marks the last row of a page. The next page starts after the row
+// with this sort key and id. Clients only see it encoded.
+type Cursor struct {
+	Sort  string     `json:"s,omitempty"`
+	Time  *time.Time `json:"t,omitempty"`
+	Value *float64   `json:"v,omitempty"`
+	ID    uint       `json:"i"`
+}
+
+// Page requests one page of a list
+type Page struct {
+	Limit int
+	After *Cursor
+}
+
+// NewPage builds a page request from the limit and cursor query parameters.
+// The limit defaults to DefaultLimit and is capped at MaxLimit.
+func NewPage(limit int, cursor string) (Page, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	page := Page{Limit: limit}
+	if cursor != "" {
+		after, err := Decode(cursor)
+		if err != nil {
+			return page, err
+		}
+		page.After = after
+	}
+
+	return page, nil
+}
+
+// AfterSort returns the cursor if it was issued for the given sort order
+func (p Page) AfterSort(sort string) (*Cursor, error) {
+	if p.After == nil {
+		return nil, nil
+	}
+	if p.After.Sort != sort {
+		return nil, ErrInvalidCursor.WithMessage("cursor belongs to a different sort order")
+	}
+	return p.After, nil
+}
+
+// Encode returns the opaque form of the cursor
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses an opaque cursor
+func Decode(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// TimeCursor returns the encoded cursor of a row sorted by a timestamp
+func TimeCursor(sort string, t time.Time, id uint) string {
+	return Cursor{Sort: sort, Time: &t, ID: id}.Encode()
+}
+
+// ValueCursor returns the encoded cursor of a row sorted by a number
+func ValueCursor(sort string, v float64, id uint) string {
+	return Cursor{Sort: sort, Value: &v, ID: id}.Encode()
+}
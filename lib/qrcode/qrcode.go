@@ -0,0 +1,44 @@
+package qrcode
+
+import (
+	"errors"
+
+	qr "github.com/skip2/go-qrcode"
+)
+
+// Errors returned by Encode
+var (
+	ErrEmpty   = errors.New("no data for a qr code")
+	ErrTooLong = errors.New("data too long for a qr code")
+)
+
+// Code is an encoded QR code, a square of dark and light modules. Codes use
+// medium (M) error correction, which survives about 15% damage, e.g. glare
+// on a phone screen.
+type Code struct {
+	modules [][]bool
+}
+
+// Encode encodes the data in the smallest version that fits
+func Encode(data []byte) (*Code, error) {
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+	code, err := qr.New(string(data), qr.Medium)
+	if err != nil {
+		return nil, ErrTooLong
+	}
+	// The renderers add the quiet zone themselves
+	code.DisableBorder = true
+	return &Code{modules: code.Bitmap()}, nil
+}
+
+// Size gives the width of the code in modules, without the quiet zone
+func (c *Code) Size() int {
+	return len(c.modules)
+}
+
+// Dark reports whether the module in column x of row y is dark
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
@@ -0,0 +1,98 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+)
+
+func TestEncodeSize(t *testing.T) {
+	// Byte mode capacity at level M of versions 1, 2 and 10, ISO/IEC 18004
+	// table 7
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{14, 1},
+		{15, 2},
+		{213, 10},
+		{214, 11},
+	}
+	for _, tt := range tests {
+		code, err := Encode(bytes.Repeat([]byte{0xe1}, tt.length))
+		if err != nil {
+			t.Fatalf("%d bytes: %v", tt.length, err)
+		}
+		if size := code.Size(); size != 17+4*tt.version {
+			t.Errorf("%d bytes: size = %d, want version %d (%d)", tt.length, size, tt.version, 17+4*tt.version)
+		}
+	}
+}
+
+func TestEncodeFinderPatterns(t *testing.T) {
+	code, err := Encode([]byte("https://smartket.vn/r/abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := code.Size()
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for y := 0; y < 7; y++ {
+			for x := 0; x < 7; x++ {
+				ring := max(abs(x-3), abs(y-3))
+				want := ring != 2
+				if got := code.Dark(corner[0]+x, corner[1]+y); got != want {
+					t.Fatalf("finder at %v: module (%d, %d) dark = %v, want %v", corner, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	if _, err := Encode(nil); !errors.Is(err, ErrEmpty) {
+		t.Errorf("empty: err = %v, want %v", err, ErrEmpty)
+	}
+	// Version 40-M holds 2331 bytes
+	if _, err := Encode(make([]byte, 2332)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("too long: err = %v, want %v", err, ErrTooLong)
+	}
+}
+
+func TestPNGMatchesModules(t *testing.T) {
+	code, err := Encode([]byte("https://smartket.vn/r/abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const scale = 3
+	data, err := code.PNG(scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	width := (code.Size() + 2*quietZone) * scale
+	if b := img.Bounds(); b.Dx() != width || b.Dy() != width {
+		t.Fatalf("image is %dx%d, want %dx%d", b.Dx(), b.Dy(), width, width)
+	}
+	for y := -quietZone; y < code.Size()+quietZone; y++ {
+		for x := -quietZone; x < code.Size()+quietZone; x++ {
+			r, _, _, _ := img.At((x+quietZone)*scale+1, (y+quietZone)*scale+1).RGBA()
+			inside := x >= 0 && y >= 0 && x < code.Size() && y < code.Size()
+			if dark := r == 0; dark != (inside && code.Dark(x, y)) {
+				t.Fatalf("pixel of module (%d, %d) dark = %v", x, y, dark)
+			}
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
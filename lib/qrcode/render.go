@@ -0,0 +1,62 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+)
+
+// quietZone is the light border scanners need around the code, in modules
+const quietZone = 4
+
+// PNG renders the code black on white with scale pixels per module
+func (c *Code) PNG(scale int) ([]byte, error) {
+	scale = max(scale, 1)
+	width := (c.Size() + 2*quietZone) * scale
+
+	img := image.NewGray(image.Rect(0, 0, width, width))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for y := 0; y < c.Size(); y++ {
+		for x := 0; x < c.Size(); x++ {
+			if !c.Dark(x, y) {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				row := img.Pix[((y+quietZone)*scale+py)*img.Stride:]
+				for px := 0; px < scale; px++ {
+					row[(x+quietZone)*scale+px] = 0
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the code black on white, scale pixels per module wide. The
+// image scales without blurring, so it can also be sized by CSS.
+func (c *Code) SVG(scale int) []byte {
+	scale = max(scale, 1)
+	width := c.Size() + 2*quietZone
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		width*scale, width*scale, width, width)
+	buf.WriteString(`<rect width="100%" height="100%" fill="#fff"/><path fill="#000" d="`)
+	for y := 0; y < c.Size(); y++ {
+		for x := 0; x < c.Size(); x++ {
+			if c.Dark(x, y) {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x+quietZone, y+quietZone)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
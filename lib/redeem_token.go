@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Errors returned while verifying redeem tokens
+var (
+	ErrRedeemTokenInvalid = errors.New("invalid redeem token")
+	ErrRedeemTokenExpired = errors.New("redeem token expired")
+)
+
+const (
+	redeemTokenVersion = 1
+	redeemPayloadBytes = 9  // version, order ID and expiry
+	redeemMACBytes     = 16 // truncated HMAC-SHA256
+)
+
+// RedeemTokenManager signs the short-lived pickup tokens customers show as a
+// QR code at the counter. A token carries the order ID and its expiry with
+// an HMAC, so it cannot be forged or moved to another order. Tokens are kept
+// to 34 characters so the QR code stays small and scans quickly.
+type RedeemTokenManager struct {
+	key []byte
+}
+
+// NewRedeemTokenManager creates a redeem token manager from the environment
+func NewRedeemTokenManager(env Env, logger Logger) RedeemTokenManager {
+	if env.RedeemTokenSecret == "" {
+		logger.Panic("REDEEM_TOKEN_SECRET is required")
+	}
+	return RedeemTokenManager{key: []byte(env.RedeemTokenSecret)}
+}
+
+// Sign issues a token for the order, valid until expiresAt
+func (m RedeemTokenManager) Sign(orderID uint, expiresAt time.Time) string {
+	token := make([]byte, redeemPayloadBytes, redeemPayloadBytes+redeemMACBytes)
+	token[0] = redeemTokenVersion
+	binary.BigEndian.PutUint32(token[1:5], uint32(orderID))
+	binary.BigEndian.PutUint32(token[5:9], uint32(expiresAt.Unix()))
+	token = append(token, m.mac(token)...)
+
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// Verify checks the signature and expiry of a token and returns its order ID
+func (m RedeemTokenManager) Verify(token string, now time.Time) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != redeemPayloadBytes+redeemMACBytes || raw[0] != redeemTokenVersion {
+		return 0, ErrRedeemTokenInvalid
+	}
+
+	payload := raw[:redeemPayloadBytes]
+	if !hmac.Equal(raw[redeemPayloadBytes:], m.mac(payload)) {
+		return 0, ErrRedeemTokenInvalid
+	}
+	if now.Unix() >= int64(binary.BigEndian.Uint32(payload[5:9])) {
+		return 0, ErrRedeemTokenExpired
+	}
+
+	return uint(binary.BigEndian.Uint32(payload[1:5])), nil
+}
+
+func (m RedeemTokenManager) mac(payload []byte) []byte {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write(payload)
+	return mac.Sum(nil)[:redeemMACBytes]
+}
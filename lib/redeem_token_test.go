@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedeemTokenRoundTrip(t *testing.T) {
+	m := RedeemTokenManager{key: []byte("secret")}
+	now := time.Unix(1_700_000_000, 0)
+
+	token := m.Sign(42, now.Add(5*time.Minute))
+	if len(token) != 34 {
+		t.Errorf("token length = %d, want 34", len(token))
+	}
+
+	orderID, err := m.Verify(token, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orderID != 42 {
+		t.Errorf("order ID = %d, want 42", orderID)
+	}
+
+	// A token only names the order it was signed for
+	other := m.Sign(43, now.Add(5*time.Minute))
+	if other == token {
+		t.Error("tokens of different orders are equal")
+	}
+	if orderID, _ := m.Verify(other, now); orderID != 43 {
+		t.Errorf("order ID = %d, want 43", orderID)
+	}
+}
+
+func TestRedeemTokenRejected(t *testing.T) {
+	m := RedeemTokenManager{key: []byte("secret")}
+	now := time.Unix(1_700_000_000, 0)
+	token := m.Sign(42, now.Add(5*time.Minute))
+
+	modify := func(change func(raw []byte)) string {
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		change(raw)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		now   time.Time
+		want  error
+	}{
+		{"tampered MAC", modify(func(raw []byte) { raw[len(raw)-1] ^= 1 }), now, ErrRedeemTokenInvalid},
+		{"other order ID", modify(func(raw []byte) { raw[4]++ }), now, ErrRedeemTokenInvalid},
+		{"extended expiry", modify(func(raw []byte) { raw[5]++ }), now, ErrRedeemTokenInvalid},
+		{"unknown version", modify(func(raw []byte) { raw[0] = 2 }), now, ErrRedeemTokenInvalid},
+		{"other key", RedeemTokenManager{key: []byte("other")}.Sign(42, now.Add(5*time.Minute)), now, ErrRedeemTokenInvalid},
+		{"truncated", token[:len(token)-2], now, ErrRedeemTokenInvalid},
+		{"not base64", "!" + token[1:], now, ErrRedeemTokenInvalid},
+		{"empty", "", now, ErrRedeemTokenInvalid},
+		{"expired", token, now.Add(5 * time.Minute), ErrRedeemTokenExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderID, err := m.Verify(tt.token, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if orderID != 0 {
+				t.Errorf("order ID = %d, want 0", orderID)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package lib
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+)
+
+// TokenClaims are the claims carried by access and refresh tokens
+type TokenClaims struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// TokenManager issues and verifies signed JWTs.
+//
+// Tokens are signed with the current key and carry its id in the `kid`
+// header. Older keys listed in JWT_VERIFY_KEYS keep verifying tokens issued
+// before a rotation until they expire.
+type TokenManager struct {
+	method     jwt.SigningMethod
+	signingKey interface{}
+	keyID      string
+	verifyKeys map[string]interface{}
+	issuer     string
+	audience   string
+}
+
+// NewTokenManager creates a token manager from the environment
+func NewTokenManager(env Env, logger Logger) TokenManager {
+	manager, err := newTokenManager(env)
+	if err != nil {
+		logger.Panic("cannot set up token signing: ", err)
+	}
+	return manager
+}
+
+func newTokenManager(env Env) (TokenManager, error) {
+	manager := TokenManager{
+		keyID:      env.JWTKeyID,
+		verifyKeys: map[string]interface{}{},
+		issuer:     env.JWTIssuer,
+		audience:   env.JWTAudience,
+	}
+
+	algorithm := strings.ToUpper(env.JWTAlgorithm)
+	switch algorithm {
+	case "", "HS256":
+		if env.JWTSecret == "" {
+			return manager, errors.New("JWT_SECRET is required for HS256")
+		}
+		manager.method = jwt.SigningMethodHS256
+		manager.signingKey = []byte(env.JWTSecret)
+		manager.verifyKeys[manager.keyID] = manager.signingKey
+	case "RS256", "EDDSA":
+		pem, err := os.ReadFile(env.JWTPrivateKeyFile)
+		if err != nil {
+			return manager, fmt.Errorf("reading JWT_PRIVATE_KEY_FILE: %w", err)
+		}
+		if algorithm == "RS256" {
+			key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return manager, err
+			}
+			manager.method = jwt.SigningMethodRS256
+			manager.signingKey = key
+			manager.verifyKeys[manager.keyID] = &key.PublicKey
+		} else {
+			key, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return manager, err
+			}
+			edKey, ok := key.(ed25519.PrivateKey)
+			if !ok {
+				return manager, errors.New("JWT_PRIVATE_KEY_FILE is not an Ed25519 key")
+			}
+			manager.method = jwt.SigningMethodEdDSA
+			manager.signingKey = edKey
+			manager.verifyKeys[manager.keyID] = edKey.Public()
+		}
+	default:
+		return manager, fmt.Errorf("unsupported JWT_ALGORITHM %q", env.JWTAlgorithm)
+	}
+
+	// Previous keys as kid=value pairs: secrets for HS256, public key files otherwise
+	for _, entry := range strings.Split(env.JWTVerifyKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return manager, fmt.Errorf("invalid JWT_VERIFY_KEYS entry %q", entry)
+		}
+		key, err := manager.parseVerifyKey(parts[1])
+		if err != nil {
+			return manager, fmt.Errorf("JWT_VERIFY_KEYS %s: %w", parts[0], err)
+		}
+		manager.verifyKeys[parts[0]] = key
+	}
+
+	return manager, nil
+}
+
+// parseVerifyKey loads a verification key for the configured algorithm
+func (m TokenManager) parseVerifyKey(value string) (interface{}, error) {
+	if m.method == jwt.SigningMethodHS256 {
+		return []byte(value), nil
+	}
+
+	pem, err := os.ReadFile(value)
+	if err != nil {
+		return nil, err
+	}
+	if m.method == jwt.SigningMethodRS256 {
+		return jwt.ParseRSAPublicKeyFromPEM(pem)
+	}
+	return jwt.ParseEdPublicKeyFromPEM(pem)
+}
+
+// Generate issues a signed token for the user valid for the given duration
+func (m TokenManager) Generate(userID uint, email string, role string, duration time.Duration) (string, error) {
+	now := time.Now()
+	claims := &TokenClaims{
+		UserID: userID,
+		Email:  email,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        utils.NewTokenID(),
+			Issuer:    m.issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	if m.audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.audience}
+	}
+
+	token := jwt.NewWithClaims(m.method, claims)
+	if m.keyID != "" {
+		token.Header["kid"] = m.keyID
+	}
+	return token.SignedString(m.signingKey)
+}
+
+// Validate verifies a token's signature, expiry, issuer and audience
+func (m TokenManager) Validate(tokenString string) (*TokenClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{m.method.Alg()}))
+
+	claims := &TokenClaims{}
+	token, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.verifyKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if m.issuer != "" && !claims.VerifyIssuer(m.issuer, true) {
+		return nil, errors.New("invalid token issuer")
+	}
+	if m.audience != "" && !claims.VerifyAudience(m.audience, true) {
+		return nil, errors.New("invalid token audience")
+	}
+
+	return claims, nil
+}
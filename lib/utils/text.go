@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// FoldVietnamese lowercases s and strips Vietnamese diacritics, so
+// "Quận Bình Thạnh" becomes "quan binh thanh"
+func FoldVietnamese(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, strings.ToLower(s))
+	if err != nil {
+		folded = strings.ToLower(s)
+	}
+	return strings.ReplaceAll(folded, "đ", "d")
+}
+
+// Tokenize folds s and splits it into lowercase ASCII words and numbers
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(FoldVietnamese(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// Slugify turns s into a lowercase ASCII slug, so "Bánh mì & Bánh ngọt"
+// becomes "banh-mi-banh-ngot"
+func Slugify(s string) string {
+	return strings.Join(Tokenize(s), "-")
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Mì Hảo Hảo", []string{"mi", "hao", "hao"}},
+		{"mi hao hao", []string{"mi", "hao", "hao"}},
+		{"Đậu hũ ĐẶC BIỆT", []string{"dau", "hu", "dac", "biet"}},
+		{"Bánh mì & trà sữa 500ml", []string{"banh", "mi", "tra", "sua", "500ml"}},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
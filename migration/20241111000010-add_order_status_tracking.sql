@@ -0,0 +1,27 @@
+-- +migrate Up
+ALTER TABLE orders
+    ADD COLUMN confirmed_at TIMESTAMP NULL,
+    ADD COLUMN ready_at TIMESTAMP NULL,
+    ADD COLUMN cancelled_at TIMESTAMP NULL,
+    ADD COLUMN cancel_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    actor VARCHAR(50) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_status_history_order_id ON order_status_history(order_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS order_status_history;
+
+ALTER TABLE orders
+    DROP COLUMN confirmed_at,
+    DROP COLUMN ready_at,
+    DROP COLUMN cancelled_at,
+    DROP COLUMN cancel_reason;
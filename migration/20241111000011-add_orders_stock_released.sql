@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE orders ADD COLUMN stock_released BOOLEAN DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE orders DROP COLUMN stock_released;
@@ -0,0 +1,20 @@
+-- +migrate Up
+ALTER TABLE sessions
+    ADD COLUMN family_id VARCHAR(64),
+    ADD COLUMN refresh_expires_at TIMESTAMP NULL,
+    ADD COLUMN rotated_at TIMESTAMP NULL;
+
+UPDATE sessions SET family_id = CONCAT('legacy-', id) WHERE family_id IS NULL;
+UPDATE sessions SET refresh_expires_at = expires_at WHERE refresh_expires_at IS NULL;
+
+CREATE INDEX idx_sessions_family_id ON sessions(family_id);
+CREATE INDEX idx_sessions_refresh_token ON sessions(refresh_token);
+
+-- +migrate Down
+DROP INDEX idx_sessions_refresh_token ON sessions;
+DROP INDEX idx_sessions_family_id ON sessions;
+
+ALTER TABLE sessions
+    DROP COLUMN family_id,
+    DROP COLUMN refresh_expires_at,
+    DROP COLUMN rotated_at;
@@ -0,0 +1,9 @@
+-- +migrate Up
+CREATE INDEX idx_orders_user_created ON orders(user_id, created_at, id);
+CREATE INDEX idx_orders_merchant_created ON orders(merchant_id, created_at, id);
+CREATE INDEX idx_products_merchant_created ON products(merchant_id, created_at, id);
+
+-- +migrate Down
+DROP INDEX idx_products_merchant_created ON products;
+DROP INDEX idx_orders_merchant_created ON orders;
+DROP INDEX idx_orders_user_created ON orders;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS markdown_rules (
+    id SERIAL PRIMARY KEY,
+    merchant_id INTEGER NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    hours_before_expiry INTEGER NOT NULL,
+    discount_percent DECIMAL(5, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (merchant_id, hours_before_expiry)
+);
+
+CREATE INDEX idx_products_active_expiry ON products(is_active, expiry_date);
+
+-- +migrate Down
+DROP INDEX idx_products_active_expiry ON products;
+DROP TABLE IF EXISTS markdown_rules;
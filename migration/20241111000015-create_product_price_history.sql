@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS product_price_history (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    old_orig_price DECIMAL(10, 2) NOT NULL,
+    new_orig_price DECIMAL(10, 2) NOT NULL,
+    old_sale_price DECIMAL(10, 2) NOT NULL,
+    new_sale_price DECIMAL(10, 2) NOT NULL,
+    actor VARCHAR(50) NOT NULL,
+    actor_id INTEGER NULL,
+    reason VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_price_history_product_id ON product_price_history(product_id, created_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS product_price_history;
@@ -0,0 +1,48 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS product_images (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    url VARCHAR(1024) NOT NULL,
+    thumbnail_url VARCHAR(1024),
+    storage_key VARCHAR(255),
+    thumbnail_key VARCHAR(255),
+    position INTEGER NOT NULL DEFAULT 0,
+    alt_text VARCHAR(255),
+    width INTEGER DEFAULT 0,
+    height INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_images_product_position ON product_images(product_id, position);
+
+-- Move the comma-separated image URLs into rows, keeping their order
+INSERT INTO product_images (product_id, url, thumbnail_url, position)
+WITH RECURSIVE split (product_id, position, url, rest) AS (
+    SELECT id, 0,
+        TRIM(SUBSTRING_INDEX(images, ',', 1)),
+        IF(LOCATE(',', images) > 0, SUBSTRING(images, LOCATE(',', images) + 1), NULL)
+    FROM products
+    WHERE images IS NOT NULL AND TRIM(images) <> ''
+    UNION ALL
+    SELECT product_id, position + 1,
+        TRIM(SUBSTRING_INDEX(rest, ',', 1)),
+        IF(LOCATE(',', rest) > 0, SUBSTRING(rest, LOCATE(',', rest) + 1), NULL)
+    FROM split
+    WHERE rest IS NOT NULL
+)
+SELECT product_id, url, url, position FROM split WHERE url <> '';
+
+ALTER TABLE products DROP COLUMN images;
+
+-- +migrate Down
+ALTER TABLE products ADD COLUMN images TEXT AFTER stock;
+
+UPDATE products p
+    JOIN (
+        SELECT product_id, GROUP_CONCAT(url ORDER BY position, id SEPARATOR ',') AS urls
+        FROM product_images
+        GROUP BY product_id
+    ) i ON i.product_id = p.id
+    SET p.images = i.urls;
+
+DROP TABLE IF EXISTS product_images;
@@ -0,0 +1,52 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    parent_id BIGINT UNSIGNED NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    name_vi VARCHAR(255) NOT NULL,
+    name_en VARCHAR(255) NOT NULL,
+    icon VARCHAR(255),
+    position INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories(id)
+);
+
+INSERT INTO categories (slug, name_vi, name_en, icon, position) VALUES
+    ('thuc-pham', 'Thực phẩm & Đồ ăn', 'Food', '🍱', 1),
+    ('banh-ngot-banh-mi', 'Bánh ngọt / Bánh mì', 'Bakery', '🥐', 2),
+    ('sua', 'Sữa & sản phẩm từ sữa', 'Dairy', '🥛', 3),
+    ('do-uong', 'Đồ uống', 'Drinks', '🥤', 4),
+    ('khac', 'Khác', 'Other', '📦', 5);
+
+INSERT INTO categories (parent_id, slug, name_vi, name_en, icon, position)
+SELECT p.id, c.slug, c.name_vi, c.name_en, c.icon, c.position
+FROM categories p
+JOIN (
+    SELECT 'rau-cu' AS slug, 'Rau củ' AS name_vi, 'Vegetables' AS name_en, '🥬' AS icon, 1 AS position
+    UNION ALL SELECT 'trai-cay', 'Trái cây', 'Fruits', '🍎', 2
+    UNION ALL SELECT 'thit-ca', 'Thịt & Cá', 'Meat & Fish', '🥩', 3
+    UNION ALL SELECT 'do-an-san', 'Đồ ăn sẵn', 'Ready meals', '🍛', 4
+) c
+WHERE p.slug = 'thuc-pham';
+
+-- Map the free-form categories onto the taxonomy
+UPDATE products SET category = CASE
+    WHEN category IN (SELECT slug FROM categories) THEN category
+    WHEN category IN ('Thực phẩm & Đồ ăn', 'food') THEN 'thuc-pham'
+    WHEN category IN ('Bánh ngọt / Bánh mì', 'bakery') THEN 'banh-ngot-banh-mi'
+    WHEN category IN ('Sữa & sản phẩm từ sữa', 'dairy') THEN 'sua'
+    WHEN category IN ('Đồ uống', 'drinks', 'beverages') THEN 'do-uong'
+    WHEN category = 'vegetables' THEN 'rau-cu'
+    WHEN category = 'fruits' THEN 'trai-cay'
+    WHEN category = 'meat' THEN 'thit-ca'
+    ELSE 'khac'
+END;
+
+ALTER TABLE products
+    ADD CONSTRAINT fk_products_category FOREIGN KEY (category) REFERENCES categories(slug) ON UPDATE CASCADE;
+
+-- +migrate Down
+ALTER TABLE products DROP FOREIGN KEY fk_products_category;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,48 @@
+-- +migrate Up
+ALTER TABLE merchants
+    ADD COLUMN verification_status VARCHAR(20) NOT NULL DEFAULT 'unsubmitted',
+    ADD COLUMN verification_note TEXT,
+    ADD COLUMN submitted_at TIMESTAMP NULL,
+    ADD COLUMN reviewed_at TIMESTAMP NULL,
+    ADD COLUMN reviewed_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL;
+
+-- Merchants verified before the review workflow count as approved
+UPDATE merchants SET verification_status = 'approved' WHERE is_verified = TRUE;
+
+CREATE INDEX idx_merchants_verification ON merchants(verification_status, submitted_at);
+
+CREATE TABLE IF NOT EXISTS merchant_documents (
+    id SERIAL PRIMARY KEY,
+    merchant_id INTEGER NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    file_name VARCHAR(255),
+    content_type VARCHAR(100) NOT NULL,
+    size INTEGER NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_merchant_documents_merchant_id ON merchant_documents(merchant_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT,
+    read_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS merchant_documents;
+DROP INDEX idx_merchants_verification ON merchants;
+ALTER TABLE merchants
+    DROP COLUMN reviewed_by,
+    DROP COLUMN reviewed_at,
+    DROP COLUMN submitted_at,
+    DROP COLUMN verification_note,
+    DROP COLUMN verification_status;
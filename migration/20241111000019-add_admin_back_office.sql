@@ -0,0 +1,29 @@
+-- +migrate Up
+ALTER TABLE products
+    ADD COLUMN unlisted_at TIMESTAMP NULL,
+    ADD COLUMN unlist_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS admin_audit_logs (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES users(id),
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id INTEGER NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_admin_audit_logs_created ON admin_audit_logs(created_at, id);
+CREATE INDEX idx_admin_audit_logs_target ON admin_audit_logs(target_type, target_id);
+CREATE INDEX idx_users_created ON users(created_at, id);
+CREATE INDEX idx_merchants_created ON merchants(created_at, id);
+CREATE INDEX idx_orders_created ON orders(created_at, id);
+
+-- +migrate Down
+DROP INDEX idx_orders_created ON orders;
+DROP INDEX idx_merchants_created ON merchants;
+DROP INDEX idx_users_created ON users;
+DROP TABLE IF EXISTS admin_audit_logs;
+ALTER TABLE products
+    DROP COLUMN unlist_reason,
+    DROP COLUMN unlisted_at;
@@ -0,0 +1,48 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS merchant_members (
+    id SERIAL PRIMARY KEY,
+    merchant_id INTEGER NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    user_id INTEGER NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    invited_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    accepted_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    revoked_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    invitation_token_hash VARCHAR(64) NOT NULL DEFAULT '',
+    invitation_expires_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_merchant_members_merchant ON merchant_members(merchant_id, status);
+CREATE INDEX idx_merchant_members_user ON merchant_members(user_id, status);
+CREATE INDEX idx_merchant_members_email ON merchant_members(email, status);
+
+-- Every existing merchant account becomes the owner of its shop
+INSERT INTO merchant_members (merchant_id, user_id, email, role, status, accepted_at)
+SELECT m.id, u.id, u.email, 'owner', 'active', m.created_at
+FROM merchants m JOIN users u ON u.id = m.user_id;
+
+-- The user who made an order status or price change, e.g. the cashier who
+-- redeemed an order. Price changes kept the merchant in actor_id, it moves
+-- to merchant_id and actor_id becomes the user, the owner for past changes.
+ALTER TABLE order_status_history ADD COLUMN actor_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE product_price_history ADD COLUMN merchant_id INTEGER NULL REFERENCES merchants(id) ON DELETE CASCADE AFTER product_id;
+UPDATE product_price_history h
+    JOIN products p ON p.id = h.product_id
+    SET h.merchant_id = p.merchant_id;
+UPDATE product_price_history h
+    JOIN merchants m ON m.id = h.actor_id
+    SET h.actor_id = m.user_id
+    WHERE h.actor = 'merchant';
+ALTER TABLE product_price_history MODIFY merchant_id INTEGER NOT NULL;
+
+-- +migrate Down
+UPDATE product_price_history
+    SET actor_id = merchant_id
+    WHERE actor = 'merchant';
+ALTER TABLE product_price_history DROP COLUMN merchant_id;
+ALTER TABLE order_status_history DROP COLUMN actor_id;
+DROP TABLE IF EXISTS merchant_members;
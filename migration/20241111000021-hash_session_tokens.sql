@@ -0,0 +1,34 @@
+-- +migrate Up
+-- Signed tokens do not fit in VARCHAR(500). Sessions keep the SHA-256 hex
+-- digest of their tokens instead, which always takes 64 characters.
+ALTER TABLE sessions
+    ADD COLUMN access_token_hash CHAR(64) NULL,
+    ADD COLUMN refresh_token_hash CHAR(64) NULL;
+
+UPDATE sessions
+SET access_token_hash = SHA2(access_token, 256),
+    refresh_token_hash = SHA2(refresh_token, 256);
+
+ALTER TABLE sessions
+    MODIFY access_token_hash CHAR(64) NOT NULL,
+    DROP COLUMN access_token,
+    DROP COLUMN refresh_token;
+
+CREATE UNIQUE INDEX idx_sessions_access_token_hash ON sessions(access_token_hash);
+CREATE UNIQUE INDEX idx_sessions_refresh_token_hash ON sessions(refresh_token_hash);
+
+-- +migrate Down
+-- Tokens cannot be recovered from their hashes, everyone signs in again
+DELETE FROM sessions;
+
+DROP INDEX idx_sessions_refresh_token_hash ON sessions;
+DROP INDEX idx_sessions_access_token_hash ON sessions;
+
+ALTER TABLE sessions
+    DROP COLUMN access_token_hash,
+    DROP COLUMN refresh_token_hash,
+    ADD COLUMN access_token VARCHAR(500) UNIQUE NOT NULL,
+    ADD COLUMN refresh_token VARCHAR(500) UNIQUE;
+
+CREATE INDEX idx_sessions_access_token ON sessions(access_token);
+CREATE INDEX idx_sessions_refresh_token ON sessions(refresh_token);
@@ -7,18 +7,25 @@ INSERT INTO users (email, password, name, phone, role) VALUES
 ON DUPLICATE KEY UPDATE email=email;
 
 -- Insert merchant profile
-INSERT INTO merchants (user_id, business_name, address, latitude, longitude, phone, is_verified)
-SELECT id, 'Gia Lạc Minimart', 'Quận 1, TP.HCM', 10.7769, 106.7009, '0901234567', 1
+INSERT INTO merchants (user_id, business_name, address, latitude, longitude, phone, is_verified, verification_status)
+SELECT id, 'Gia Lạc Minimart', 'Quận 1, TP.HCM', 10.7769, 106.7009, '0901234567', 1, 'approved'
 FROM users WHERE email = 'merchant@smartket.com'
 ON DUPLICATE KEY UPDATE business_name=business_name;
 
+-- The merchant account owns its shop
+INSERT INTO merchant_members (merchant_id, user_id, email, role, status, accepted_at)
+SELECT m.id, u.id, u.email, 'owner', 'active', NOW()
+FROM merchants m JOIN users u ON u.id = m.user_id
+WHERE u.email = 'merchant@smartket.com'
+AND NOT EXISTS (SELECT 1 FROM merchant_members mm WHERE mm.merchant_id = m.id AND mm.role = 'owner');
+
 -- Insert sample products
 INSERT INTO products (merchant_id, name, description, category, orig_price, sale_price, discount, stock, expiry_date, is_active) 
 SELECT 
     m.id,
     'Mì Hảo Hảo Tôm Chua Cay',
     'Gói mì ăn liền hương vị tôm chua cay',
-    'Thực phẩm & Đồ ăn',
+    'thuc-pham',
     8000,
     6000,
     25,
@@ -33,7 +40,7 @@ SELECT
     m.id,
     'Cơm Bento Trứng Cuộn',
     'Hộp cơm bento với trứng cuộn Nhật Bản',
-    'Thực phẩm & Đồ ăn',
+    'do-an-san',
     50000,
     35000,
     30,
@@ -48,7 +55,7 @@ SELECT
     m.id,
     'Bánh Mì Việt Nam',
     'Bánh mì thịt nguội truyền thống',
-    'Bánh ngọt / Bánh mì',
+    'banh-ngot-banh-mi',
     25000,
     18000,
     28,
@@ -63,7 +70,7 @@ SELECT
     m.id,
     'Sữa Tươi Vinamilk',
     'Hộp sữa tươi không đường 1L',
-    'Sữa & sản phẩm từ sữa',
+    'sua',
     32000,
     28000,
     13,
@@ -78,7 +85,7 @@ SELECT
     m.id,
     'Cà Phê Đen Đá',
     'Ly cà phê đen đá truyền thống',
-    'Đồ uống',
+    'do-uong',
     20000,
     15000,
     25,
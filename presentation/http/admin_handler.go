@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminService admin.Service
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService admin.Service) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// ListUsers lists and searches users (admin only)
+// @Summary List users
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "Part of the email, name or phone"
+// @Param role query string false "customer, merchant or admin"
+// @Param is_active query bool false "Active or deactivated users only"
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {array} auth.User
+// @Router /api/admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	isActive, err := boolQuery(c, "is_active")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter := &auth.UserFilter{
+		Query:    c.Query("q"),
+		Role:     c.Query("role"),
+		IsActive: isActive,
+	}
+	users, next, err := h.adminService.ListUsers(filter, page)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": users, "next_cursor": next})
+}
+
+// DeactivateUser deactivates a user and ends their sessions (admin only)
+// @Summary Deactivate a user
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body admin.ReasonRequest true "Reason"
+// @Success 200 {object} auth.User
+// @Router /api/admin/users/{id}/deactivate [post]
+func (h *AdminHandler) DeactivateUser(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("user"))
+		return
+	}
+
+	var req admin.ReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	user, err := h.adminService.DeactivateUser(adminID.(uint), uint(userID), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// ActivateUser reactivates a deactivated user (admin only)
+// @Summary Activate a user
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} auth.User
+// @Router /api/admin/users/{id}/activate [post]
+func (h *AdminHandler) ActivateUser(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("user"))
+		return
+	}
+
+	user, err := h.adminService.ActivateUser(adminID.(uint), uint(userID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// ListMerchants lists and searches merchants (admin only)
+// @Summary List merchants
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "Part of the shop name or phone"
+// @Param verification_status query string false "unsubmitted, pending, approved or rejected"
+// @Param is_active query bool false "Active or inactive merchants only"
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {array} merchant.Merchant
+// @Router /api/admin/merchants [get]
+func (h *AdminHandler) ListMerchants(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	isActive, err := boolQuery(c, "is_active")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter := &merchant.Filter{
+		Query:              c.Query("q"),
+		VerificationStatus: c.Query("verification_status"),
+		IsActive:           isActive,
+	}
+	merchants, next, err := h.adminService.ListMerchants(filter, page)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": merchants, "next_cursor": next})
+}
+
+// ListOrders lists and searches orders (admin only)
+// @Summary List orders
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "Part of the order code"
+// @Param status query string false "Order status"
+// @Param user_id query int false "Customer ID"
+// @Param merchant_id query int false "Merchant ID"
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {array} order.Order
+// @Router /api/admin/orders [get]
+func (h *AdminHandler) ListOrders(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	userID, err := idQuery(c, "user_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	merchantID, err := idQuery(c, "merchant_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter := &order.Filter{
+		Query:      c.Query("q"),
+		Status:     order.Status(c.Query("status")),
+		UserID:     userID,
+		MerchantID: merchantID,
+	}
+	orders, next, err := h.adminService.ListOrders(filter, page)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": orders, "next_cursor": next})
+}
+
+// CancelOrder cancels an open order in any status (admin only)
+// @Summary Force-cancel an order
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body admin.ReasonRequest true "Reason"
+// @Success 200 {object} order.Order
+// @Router /api/admin/orders/{id}/cancel [post]
+func (h *AdminHandler) CancelOrder(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("order"))
+		return
+	}
+
+	var req admin.ReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	ord, err := h.adminService.CancelOrder(adminID.(uint), uint(orderID), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": ord})
+}
+
+// UnlistProduct takes a product down from the public listing (admin only)
+// @Summary Unlist a product
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body admin.ReasonRequest true "Reason"
+// @Success 200 {object} product.Product
+// @Router /api/admin/products/{id}/unlist [post]
+func (h *AdminHandler) UnlistProduct(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("product"))
+		return
+	}
+
+	var req admin.ReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	prod, err := h.adminService.UnlistProduct(adminID.(uint), uint(productID), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": prod})
+}
+
+// RelistProduct puts an unlisted product back (admin only)
+// @Summary Relist a product
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} product.Product
+// @Router /api/admin/products/{id}/relist [post]
+func (h *AdminHandler) RelistProduct(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("product"))
+		return
+	}
+
+	prod, err := h.adminService.RelistProduct(adminID.(uint), uint(productID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": prod})
+}
+
+// GetStats gets an overview of the platform (admin only)
+// @Summary Get platform stats
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} admin.Stats
+// @Router /api/admin/stats [get]
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	stats, err := h.adminService.GetStats()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": stats})
+}
+
+// GetAuditLogs lists the admin audit log, newest first (admin only)
+// @Summary Get the admin audit log
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param admin_id query int false "Admin ID"
+// @Param action query string false "Action, e.g. user.deactivate"
+// @Param target_type query string false "user, merchant, order, product or category"
+// @Param target_id query int false "Target ID"
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {array} admin.AuditLog
+// @Router /api/admin/audit-logs [get]
+func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	adminID, err := idQuery(c, "admin_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	targetID, err := idQuery(c, "target_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter := &admin.AuditFilter{
+		AdminID:    adminID,
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   targetID,
+	}
+	logs, next, err := h.adminService.GetAuditLogs(filter, page)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": logs, "next_cursor": next})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/category"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService category.Service
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService category.Service) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// GetCategories gets the tree of active categories
+// @Summary Get the category tree
+// @Tags categories
+// @Produce json
+// @Success 200 {array} category.Category
+// @Router /api/categories [get]
+func (h *CategoryHandler) GetCategories(c *gin.Context) {
+	tree, err := h.categoryService.GetTree(false)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tree})
+}
+
+// GetAllCategories gets the tree of all categories, inactive ones included (admin only)
+// @Summary Get the full category tree
+// @Tags categories
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} category.Category
+// @Router /api/admin/categories [get]
+func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
+	tree, err := h.categoryService.GetTree(true)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tree})
+}
+
+// CreateCategory creates a category (admin only)
+// @Summary Create a category
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body category.CreateCategoryRequest true "Category details"
+// @Success 201 {object} category.Category
+// @Router /api/admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	var req category.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	cat, err := h.categoryService.CreateCategory(adminID.(uint), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": cat})
+}
+
+// UpdateCategory updates a category (admin only)
+// @Summary Update a category
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param request body category.UpdateCategoryRequest true "Category details"
+// @Success 200 {object} category.Category
+// @Router /api/admin/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("category"))
+		return
+	}
+
+	var req category.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	cat, err := h.categoryService.UpdateCategory(adminID.(uint), uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": cat})
+}
+
+// DeleteCategory deletes a category without subcategories or products (admin only)
+// @Summary Delete a category
+// @Tags categories
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} map[string]string
+// @Router /api/admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("category"))
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(adminID.(uint), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully"})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errMerchantUnauthenticated = apperror.Unauthorized("unauthenticated", "merchant not authenticated")
+
+// invalidRequest wraps a request binding error
+func invalidRequest(err error) error {
+	return apperror.BadRequest("invalid_request", err.Error())
+}
+
+// invalidID reports a malformed ID path parameter
+func invalidID(name string) error {
+	return apperror.BadRequest("invalid_id", "invalid "+name+" id")
+}
+
+// pageFromQuery reads the limit and cursor query parameters
+func pageFromQuery(c *gin.Context) (pagination.Page, error) {
+	limit := 0
+	if l := c.Query("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil {
+			return pagination.Page{}, apperror.BadRequest("invalid_limit", "limit must be a number")
+		}
+	}
+	return pagination.NewPage(limit, c.Query("cursor"))
+}
+
+// boolQuery reads an optional true/false query parameter
+func boolQuery(c *gin.Context, name string) (*bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, apperror.BadRequest("invalid_filter", name+" must be true or false")
+	}
+	return &b, nil
+}
+
+// idQuery reads an optional ID query parameter, zero when absent
+func idQuery(c *gin.Context, name string) (uint, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, apperror.BadRequest("invalid_filter", name+" must be an id")
+	}
+	return uint(id), nil
+}
+
+// merchantStaff reads the shop and the staff user acting for it, set by the
+// auth and merchant context middlewares
+func merchantStaff(c *gin.Context) (uint, uint, bool) {
+	merchantID, exists := c.Get("merchantID")
+	staffID, staffExists := c.Get("userID")
+	if !exists || !staffExists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return 0, 0, false
+	}
+	return merchantID.(uint), staffID.(uint), true
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/geocoding"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GeocodeHandler struct {
+	geocodingService geocoding.Service
+}
+
+// NewGeocodeHandler creates a new geocode handler
+func NewGeocodeHandler(geocodingService geocoding.Service) *GeocodeHandler {
+	return &GeocodeHandler{geocodingService: geocodingService}
+}
+
+// Geocode looks up coordinates for a free-text address
+// @Summary Geocode an address
+// @Tags geocode
+// @Produce json
+// @Param q query string true "Address or place name"
+// @Success 200 {array} geocoding.Result
+// @Router /api/geocode [get]
+func (h *GeocodeHandler) Geocode(c *gin.Context) {
+	results, err := h.geocodingService.Search(c.Query("q"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/location"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LocationHandler struct {
+	locationService location.Service
+}
+
+// NewLocationHandler creates a new location handler
+func NewLocationHandler(locationService location.Service) *LocationHandler {
+	return &LocationHandler{locationService: locationService}
+}
+
+// GetLocations lists the authenticated user's saved locations
+// @Summary Get user's locations
+// @Tags locations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} location.Location
+// @Router /api/locations [get]
+func (h *LocationHandler) GetLocations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	locations, err := h.locationService.GetUserLocations(userID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": locations})
+}
+
+// AddLocation saves a new location for the authenticated user
+// @Summary Add a location
+// @Tags locations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body location.AddLocationRequest true "Location details"
+// @Success 201 {object} location.Location
+// @Router /api/locations [post]
+func (h *LocationHandler) AddLocation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	var req location.AddLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	loc, err := h.locationService.AddLocation(userID.(uint), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": loc})
+}
+
+// UpdateLocation updates one of the authenticated user's locations
+// @Summary Update a location
+// @Tags locations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Location ID"
+// @Param request body location.UpdateLocationRequest true "Fields to update"
+// @Success 200 {object} location.Location
+// @Router /api/locations/{id} [put]
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("location"))
+		return
+	}
+
+	var req location.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	loc, err := h.locationService.UpdateLocation(userID.(uint), uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": loc})
+}
+
+// SetDefaultLocation makes a location the authenticated user's default
+// @Summary Set default location
+// @Tags locations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Location ID"
+// @Success 200
+// @Router /api/locations/{id}/default [post]
+func (h *LocationHandler) SetDefaultLocation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("location"))
+		return
+	}
+
+	if err := h.locationService.SetDefaultLocation(userID.(uint), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Default location updated"})
+}
+
+// DeleteLocation deletes one of the authenticated user's locations
+// @Summary Delete a location
+// @Tags locations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Location ID"
+// @Success 200
+// @Router /api/locations/{id} [delete]
+func (h *LocationHandler) DeleteLocation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("location"))
+		return
+	}
+
+	if err := h.locationService.DeleteLocation(userID.(uint), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Location deleted"})
+}
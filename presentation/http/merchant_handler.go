@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
 
 	"github.com/gin-gonic/gin"
@@ -10,13 +12,15 @@ import (
 
 type MerchantHandler struct {
 	merchantService merchant.Service
+	staffService    merchant.StaffService
 	authService     auth.Service
 }
 
 // NewMerchantHandler creates a new merchant handler
-func NewMerchantHandler(merchantService merchant.Service, authService auth.Service) *MerchantHandler {
+func NewMerchantHandler(merchantService merchant.Service, staffService merchant.StaffService, authService auth.Service) *MerchantHandler {
 	return &MerchantHandler{
 		merchantService: merchantService,
+		staffService:    staffService,
 		authService:     authService,
 	}
 }
@@ -32,13 +36,13 @@ func NewMerchantHandler(merchantService merchant.Service, authService auth.Servi
 func (h *MerchantHandler) RegisterMerchant(c *gin.Context) {
 	var req merchant.RegisterMerchantRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 
 	merch, err := h.merchantService.RegisterMerchant(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -56,19 +60,22 @@ func (h *MerchantHandler) RegisterMerchant(c *gin.Context) {
 func (h *MerchantHandler) LoginMerchant(c *gin.Context) {
 	var req auth.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 
 	response, err := h.authService.Login(&req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	// Verify user is a merchant
-	if response.User.Role != "merchant" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "not a merchant account"})
+	// Verify user works for a shop, as its owner or a staff member
+	if _, err := h.staffService.GetMembership(response.User.ID); err != nil {
+		if errors.Is(err, merchant.ErrMemberNotFound) {
+			err = apperror.Unauthorized("not_merchant", "not a merchant account")
+		}
+		_ = c.Error(err)
 		return
 	}
 
@@ -83,15 +90,15 @@ func (h *MerchantHandler) LoginMerchant(c *gin.Context) {
 // @Success 200 {object} merchant.Merchant
 // @Router /api/merchant/profile [get]
 func (h *MerchantHandler) GetMerchantProfile(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	merchantID, exists := c.Get("merchantID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "merchant not authenticated"})
+		_ = c.Error(errMerchantUnauthenticated)
 		return
 	}
 
-	merch, err := h.merchantService.GetMerchantByUserID(userID.(uint))
+	merch, err := h.merchantService.GetMerchantByID(merchantID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "merchant not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -108,26 +115,20 @@ func (h *MerchantHandler) GetMerchantProfile(c *gin.Context) {
 // @Success 200
 // @Router /api/merchant/profile [put]
 func (h *MerchantHandler) UpdateMerchantProfile(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	merchantID, exists := c.Get("merchantID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "merchant not authenticated"})
-		return
-	}
-
-	merch, err := h.merchantService.GetMerchantByUserID(userID.(uint))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "merchant not found"})
+		_ = c.Error(errMerchantUnauthenticated)
 		return
 	}
 
 	var req merchant.UpdateMerchantRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 
-	if err := h.merchantService.UpdateMerchant(merch.ID, &req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.merchantService.UpdateMerchant(merchantID.(uint), &req); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
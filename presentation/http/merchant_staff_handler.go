@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MerchantStaffHandler struct {
+	staffService merchant.StaffService
+}
+
+// NewMerchantStaffHandler creates a new merchant staff handler
+func NewMerchantStaffHandler(staffService merchant.StaffService) *MerchantStaffHandler {
+	return &MerchantStaffHandler{staffService: staffService}
+}
+
+// GetMembers lists the members and pending invitations of the shop (owner only)
+// @Summary Get shop members
+// @Tags merchant
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} merchant.Member
+// @Router /api/merchant/members [get]
+func (h *MerchantStaffHandler) GetMembers(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	members, err := h.staffService.GetMembers(merchantID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": members})
+}
+
+// InviteMember invites a user to the shop by email (owner only)
+// @Summary Invite a shop member
+// @Tags merchant
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body merchant.InviteMemberRequest true "Email and role"
+// @Success 201 {object} merchant.Member
+// @Router /api/merchant/members [post]
+func (h *MerchantStaffHandler) InviteMember(c *gin.Context) {
+	merchantID, ownerID, ok := merchantStaff(c)
+	if !ok {
+		return
+	}
+
+	var req merchant.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	member, err := h.staffService.InviteMember(merchantID, ownerID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": member})
+}
+
+// UpdateMember changes the role of a member (owner only)
+// @Summary Update a shop member
+// @Tags merchant
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Member ID"
+// @Param request body merchant.UpdateMemberRequest true "New role"
+// @Success 200 {object} merchant.Member
+// @Router /api/merchant/members/{id} [put]
+func (h *MerchantStaffHandler) UpdateMember(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("member"))
+		return
+	}
+
+	var req merchant.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	member, err := h.staffService.UpdateMember(merchantID.(uint), uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// RevokeMember removes a member or withdraws an invitation (owner only)
+// @Summary Revoke a shop member
+// @Tags merchant
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Member ID"
+// @Success 200 {object} map[string]string
+// @Router /api/merchant/members/{id} [delete]
+func (h *MerchantStaffHandler) RevokeMember(c *gin.Context) {
+	merchantID, ownerID, ok := merchantStaff(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("member"))
+		return
+	}
+
+	if err := h.staffService.RevokeMember(merchantID, uint(id), ownerID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member revoked"})
+}
+
+// GetInvitations lists the shop invitations sent to the user's email
+// @Summary Get my shop invitations
+// @Tags merchant
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} merchant.Member
+// @Router /api/invitations [get]
+func (h *MerchantStaffHandler) GetInvitations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	invitations, err := h.staffService.GetInvitations(userID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": invitations})
+}
+
+// AcceptInvitation joins the inviting shop
+// @Summary Accept a shop invitation
+// @Tags merchant
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Param request body merchant.AnswerInvitationRequest true "Token of the invitation email"
+// @Success 200 {object} merchant.Member
+// @Router /api/invitations/{id}/accept [post]
+func (h *MerchantStaffHandler) AcceptInvitation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("invitation"))
+		return
+	}
+
+	var req merchant.AnswerInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	member, err := h.staffService.AcceptInvitation(userID.(uint), uint(id), req.Token)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// DeclineInvitation turns down a shop invitation
+// @Summary Decline a shop invitation
+// @Tags merchant
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Param request body merchant.AnswerInvitationRequest true "Token of the invitation email"
+// @Success 200 {object} map[string]string
+// @Router /api/invitations/{id}/decline [post]
+func (h *MerchantStaffHandler) DeclineInvitation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("invitation"))
+		return
+	}
+
+	var req merchant.AnswerInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	if err := h.staffService.DeclineInvitation(userID.(uint), uint(id), req.Token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation declined"})
+}
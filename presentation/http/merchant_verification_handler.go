@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/media"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxDocumentBody caps the multipart body of a document upload, leaving room
+// for the form fields around the file
+const maxDocumentBody = merchant.MaxDocumentBytes + 1<<20
+
+type MerchantVerificationHandler struct {
+	verificationService merchant.VerificationService
+}
+
+// NewMerchantVerificationHandler creates a new merchant verification handler
+func NewMerchantVerificationHandler(verificationService merchant.VerificationService) *MerchantVerificationHandler {
+	return &MerchantVerificationHandler{verificationService: verificationService}
+}
+
+// UploadDocument uploads a KYC document (merchant only)
+// @Summary Upload a verification document
+// @Tags merchant
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param type formData string true "business_license, food_safety_certificate or other"
+// @Param file formData file true "PDF, JPEG or PNG file, at most 10 MB"
+// @Success 201 {object} merchant.Document
+// @Router /api/merchant/documents [post]
+func (h *MerchantVerificationHandler) UploadDocument(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDocumentBody)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			_ = c.Error(media.ErrFileTooLarge)
+			return
+		}
+		_ = c.Error(media.ErrFileRequired)
+		return
+	}
+	if header.Size > merchant.MaxDocumentBytes {
+		_ = c.Error(media.ErrFileTooLarge)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, merchant.MaxDocumentBytes+1))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	upload := &merchant.DocumentUpload{
+		Type:     c.PostForm("type"),
+		FileName: header.Filename,
+		Data:     data,
+	}
+	document, err := h.verificationService.UploadDocument(merchantID.(uint), upload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": document})
+}
+
+// GetVerification gets the verification status and documents (merchant only)
+// @Summary Get merchant verification status
+// @Tags merchant
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} merchant.Merchant
+// @Router /api/merchant/verification [get]
+func (h *MerchantVerificationHandler) GetVerification(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	merch, err := h.verificationService.GetVerification(merchantID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": merch})
+}
+
+// SubmitForReview submits the uploaded documents for review (merchant only)
+// @Summary Submit merchant for verification
+// @Tags merchant
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} merchant.Merchant
+// @Router /api/merchant/verification/submit [post]
+func (h *MerchantVerificationHandler) SubmitForReview(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	merch, err := h.verificationService.SubmitForReview(merchantID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": merch})
+}
+
+// GetPendingMerchants lists the merchants waiting for review (admin only)
+// @Summary Get merchants pending verification
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} merchant.Merchant
+// @Router /api/admin/merchants/pending [get]
+func (h *MerchantVerificationHandler) GetPendingMerchants(c *gin.Context) {
+	merchants, err := h.verificationService.GetPendingMerchants()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": merchants})
+}
+
+// GetDocument downloads a KYC document of a merchant (admin only)
+// @Summary Download a merchant verification document
+// @Tags admin
+// @Security BearerAuth
+// @Produce application/pdf,image/jpeg,image/png
+// @Param id path int true "Merchant ID"
+// @Param documentId path int true "Document ID"
+// @Success 200 {file} file
+// @Router /api/admin/merchants/{id}/documents/{documentId} [get]
+func (h *MerchantVerificationHandler) GetDocument(c *gin.Context) {
+	merchantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("merchant"))
+		return
+	}
+
+	documentID, err := strconv.ParseUint(c.Param("documentId"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("document"))
+		return
+	}
+
+	document, data, err := h.verificationService.GetDocumentFile(uint(merchantID), uint(documentID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": document.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, document.ContentType, data)
+}
+
+// ApproveMerchant approves a pending merchant (admin only)
+// @Summary Approve a merchant
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Merchant ID"
+// @Success 200 {object} merchant.Merchant
+// @Router /api/admin/merchants/{id}/approve [post]
+func (h *MerchantVerificationHandler) ApproveMerchant(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	merchantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("merchant"))
+		return
+	}
+
+	merch, err := h.verificationService.Approve(uint(merchantID), adminID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": merch})
+}
+
+// RejectMerchant rejects a pending merchant with a reason (admin only)
+// @Summary Reject a merchant
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Merchant ID"
+// @Param request body merchant.RejectMerchantRequest true "Rejection reason"
+// @Success 200 {object} merchant.Merchant
+// @Router /api/admin/merchants/{id}/reject [post]
+func (h *MerchantVerificationHandler) RejectMerchant(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	merchantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("merchant"))
+		return
+	}
+
+	var req merchant.RejectMerchantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	merch, err := h.verificationService.Reject(uint(merchantID), adminID.(uint), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": merch})
+}
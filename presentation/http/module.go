@@ -9,5 +9,13 @@ var Module = fx.Options(
 	fx.Provide(NewAuthHandler),
 	fx.Provide(NewProductHandler),
 	fx.Provide(NewMerchantHandler),
+	fx.Provide(NewMerchantVerificationHandler),
+	fx.Provide(NewMerchantStaffHandler),
 	fx.Provide(NewOrderHandler),
+	fx.Provide(NewLocationHandler),
+	fx.Provide(NewGeocodeHandler),
+	fx.Provide(NewProductImageHandler),
+	fx.Provide(NewCategoryHandler),
+	fx.Provide(NewNotificationHandler),
+	fx.Provide(NewAdminHandler),
 )
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/notification"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService notification.Service
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService notification.Service) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// GetNotifications lists the authenticated user's notifications, newest first
+// @Summary Get user's notifications
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {array} notification.Notification
+// @Router /api/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	notifications, next, err := h.notificationService.GetUserNotifications(userID.(uint), page)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": notifications, "next_cursor": next})
+}
+
+// MarkRead marks a notification as read
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Router /api/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("notification"))
+		return
+	}
+
+	if err := h.notificationService.MarkRead(userID.(uint), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
@@ -1,13 +1,22 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/apperror"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/qrcode"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxQRScale caps the pixels per module of a QR code image
+const maxQRScale = 20
+
 type OrderHandler struct {
 	orderService order.Service
 }
@@ -29,19 +38,19 @@ func NewOrderHandler(orderService order.Service) *OrderHandler {
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		_ = c.Error(auth.ErrUnauthenticated)
 		return
 	}
 
 	var req order.CreateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 
 	ord, err := h.orderService.CreateOrder(userID.(uint), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -59,93 +68,303 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 func (h *OrderHandler) GetOrder(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order id"})
+		_ = c.Error(invalidID("order"))
 		return
 	}
 
 	ord, err := h.orderService.GetOrderByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": ord})
 }
 
-// GetUserOrders gets all orders for the authenticated user
+// GetOrderQR gets the pickup QR code of an order, holding a signed token
+// valid for a few minutes
+// @Summary Get order pickup QR code
+// @Tags orders
+// @Security BearerAuth
+// @Produce image/png,image/svg+xml
+// @Param id path int true "Order ID"
+// @Param format query string false "png or svg" default(png)
+// @Param size query int false "Pixels per module, 1 to 20" default(8)
+// @Success 200 {file} file
+// @Header 200 {string} X-Expires-At "Expiry of the token, RFC 3339"
+// @Router /api/orders/{id}/qr [get]
+func (h *OrderHandler) GetOrderQR(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("order"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "png")
+	if format != "png" && format != "svg" {
+		_ = c.Error(apperror.BadRequest("invalid_format", "format must be png or svg"))
+		return
+	}
+	scale, err := strconv.Atoi(c.DefaultQuery("size", "8"))
+	if err != nil || scale < 1 || scale > maxQRScale {
+		_ = c.Error(apperror.BadRequest("invalid_size", "size must be between 1 and 20"))
+		return
+	}
+
+	token, err := h.orderService.GetRedeemToken(userID.(uint), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	code, err := qrcode.Encode([]byte(token.Token))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// The code is single-use and short-lived, so it must never be cached
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Expires-At", token.ExpiresAt.UTC().Format(time.RFC3339))
+
+	if format == "svg" {
+		c.Data(http.StatusOK, "image/svg+xml", code.SVG(scale))
+		return
+	}
+	data, err := code.PNG(scale)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", data)
+}
+
+// GetUserOrders gets a page of orders for the authenticated user
 // @Summary Get user's orders
 // @Tags orders
 // @Security BearerAuth
 // @Produce json
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {array} order.Order
 // @Router /api/orders [get]
 func (h *OrderHandler) GetUserOrders(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		_ = c.Error(auth.ErrUnauthenticated)
 		return
 	}
 
-	orders, err := h.orderService.GetUserOrders(userID.(uint))
+	page, err := pageFromQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": orders})
+	orders, next, err := h.orderService.GetUserOrders(userID.(uint), page)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": orders, "next_cursor": next})
 }
 
-// GetMerchantOrders gets all orders for the merchant
+// GetMerchantOrders gets a page of orders for the merchant
 // @Summary Get merchant's orders
 // @Tags orders
 // @Security BearerAuth
 // @Produce json
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {array} order.Order
 // @Router /api/merchant/orders [get]
 func (h *OrderHandler) GetMerchantOrders(c *gin.Context) {
 	merchantID, exists := c.Get("merchantID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "merchant not authenticated"})
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	orders, err := h.orderService.GetMerchantOrders(merchantID.(uint))
+	orders, next, err := h.orderService.GetMerchantOrders(merchantID.(uint), page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": orders})
+	c.JSON(http.StatusOK, gin.H{"data": orders, "next_cursor": next})
 }
 
-// RedeemOrder redeems an order (merchant confirms pickup)
+// RedeemOrder redeems an order with the token of its pickup QR code
+// (merchant confirms pickup)
 // @Summary Redeem/confirm order
 // @Tags orders
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param request body order.RedeemOrderRequest true "Order code"
-// @Success 200
+// @Param request body order.RedeemOrderRequest true "Scanned token"
+// @Success 200 {object} order.Order
 // @Router /api/merchant/orders/redeem [post]
 func (h *OrderHandler) RedeemOrder(c *gin.Context) {
-	merchantID, exists := c.Get("merchantID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "merchant not authenticated"})
+	merchantID, staffID, ok := merchantStaff(c)
+	if !ok {
 		return
 	}
 
 	var req order.RedeemOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	ord, err := h.orderService.RedeemOrder(merchantID, staffID, req.Token)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": ord})
+}
+
+// CancelOrder cancels a pending order
+// @Summary Cancel order
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body order.CancelOrderRequest false "Cancel reason"
+// @Success 200
+// @Router /api/orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("order"))
+		return
+	}
+
+	var req order.CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	if err := h.orderService.CancelOrder(userID.(uint), uint(id), req.Reason); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "order cancelled"})
+}
+
+// ConfirmOrder accepts a pending order
+// @Summary Confirm order
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200
+// @Router /api/merchant/orders/{id}/confirm [post]
+func (h *OrderHandler) ConfirmOrder(c *gin.Context) {
+	merchantID, staffID, ok := merchantStaff(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("order"))
+		return
+	}
+
+	if err := h.orderService.ConfirmOrder(merchantID, staffID, uint(id)); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	if err := h.orderService.RedeemOrder(merchantID.(uint), req.OrderCode); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	c.JSON(http.StatusOK, gin.H{"message": "order confirmed"})
+}
+
+// MarkOrderReady marks an order as ready for pickup
+// @Summary Mark order ready
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200
+// @Router /api/merchant/orders/{id}/ready [post]
+func (h *OrderHandler) MarkOrderReady(c *gin.Context) {
+	merchantID, staffID, ok := merchantStaff(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "order redeemed successfully"})
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("order"))
+		return
+	}
+
+	if err := h.orderService.MarkOrderReady(merchantID, staffID, uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "order marked as ready"})
+}
+
+// RejectOrder rejects an order that has not been picked up
+// @Summary Reject order
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body order.CancelOrderRequest false "Reject reason"
+// @Success 200
+// @Router /api/merchant/orders/{id}/reject [post]
+func (h *OrderHandler) RejectOrder(c *gin.Context) {
+	merchantID, staffID, ok := merchantStaff(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("order"))
+		return
+	}
+
+	var req order.CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	if err := h.orderService.RejectOrder(merchantID, staffID, uint(id), req.Reason); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "order rejected"})
 }
 
 // AddToCart adds an item to cart
@@ -160,41 +379,41 @@ func (h *OrderHandler) RedeemOrder(c *gin.Context) {
 func (h *OrderHandler) AddToCart(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		_ = c.Error(auth.ErrUnauthenticated)
 		return
 	}
 
 	var req order.AddToCartRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 
 	if err := h.orderService.AddToCart(userID.(uint), &req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "item added to cart"})
 }
 
-// GetCart gets user's cart
+// GetCart gets user's cart grouped by shop
 // @Summary Get cart
 // @Tags cart
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {object} order.Cart
+// @Success 200 {object} order.CartView
 // @Router /api/cart [get]
 func (h *OrderHandler) GetCart(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		_ = c.Error(auth.ErrUnauthenticated)
 		return
 	}
 
 	cart, err := h.orderService.GetCart(userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -214,24 +433,24 @@ func (h *OrderHandler) GetCart(c *gin.Context) {
 func (h *OrderHandler) UpdateCartItem(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		_ = c.Error(auth.ErrUnauthenticated)
 		return
 	}
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		_ = c.Error(invalidID("item"))
 		return
 	}
 
 	var req order.UpdateCartItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 
 	if err := h.orderService.UpdateCartItem(userID.(uint), uint(id), req.Quantity); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -248,24 +467,55 @@ func (h *OrderHandler) UpdateCartItem(c *gin.Context) {
 func (h *OrderHandler) RemoveCartItem(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		_ = c.Error(auth.ErrUnauthenticated)
 		return
 	}
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		_ = c.Error(invalidID("item"))
 		return
 	}
 
 	if err := h.orderService.RemoveCartItem(userID.(uint), uint(id)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "cart item removed"})
 }
 
+// Checkout places orders from the cart, one per merchant
+// @Summary Checkout cart
+// @Tags cart
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body order.CheckoutRequest true "Checkout details"
+// @Success 201 {object} order.CheckoutResponse
+// @Router /api/cart/checkout [post]
+func (h *OrderHandler) Checkout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		_ = c.Error(auth.ErrUnauthenticated)
+		return
+	}
+
+	var req order.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	response, err := h.orderService.Checkout(userID.(uint), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": response})
+}
+
 // ClearCart clears user's cart
 // @Summary Clear cart
 // @Tags cart
@@ -275,12 +525,12 @@ func (h *OrderHandler) RemoveCartItem(c *gin.Context) {
 func (h *OrderHandler) ClearCart(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		_ = c.Error(auth.ErrUnauthenticated)
 		return
 	}
 
 	if err := h.orderService.ClearCart(userID.(uint)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
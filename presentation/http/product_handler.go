@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
 
 	"github.com/gin-gonic/gin"
@@ -27,21 +28,20 @@ func NewProductHandler(productService product.Service) *ProductHandler {
 // @Success 201 {object} product.Product
 // @Router /api/merchant/products [post]
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
-	merchantID, exists := c.Get("merchantID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "merchant not authenticated"})
+	merchantID, staffID, ok := merchantStaff(c)
+	if !ok {
 		return
 	}
 
 	var req product.CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 
-	prod, err := h.productService.CreateProduct(merchantID.(uint), &req)
+	prod, err := h.productService.CreateProduct(merchantID, staffID, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -58,13 +58,13 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product id"})
+		_ = c.Error(invalidID("product"))
 		return
 	}
 
 	prod, err := h.productService.GetProductByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -80,15 +80,28 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 // @Param min_price query number false "Minimum price"
 // @Param max_price query number false "Maximum price"
 // @Param merchant_id query int false "Merchant ID"
-// @Param limit query int false "Limit" default(20)
-// @Param offset query int false "Offset" default(0)
+// @Param lat query number false "Latitude to search around"
+// @Param lng query number false "Longitude to search around"
+// @Param radius query number false "Search radius in km" default(5)
+// @Param location_id query int false "Saved location to search around (requires token)"
+// @Param min_discount query number false "Minimum discount percentage"
+// @Param expiring_within query int false "Only products expiring within this many hours"
+// @Param in_stock query bool false "Only products in stock"
+// @Param verified query bool false "Deprecated, has no effect: every listed product belongs to a verified merchant"
+// @Param sort query string false "Sort order" Enums(relevance, newest, discount, price_asc, price_desc, expiry, distance)
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {array} product.Product
 // @Router /api/products/search [get]
 func (h *ProductHandler) SearchProducts(c *gin.Context) {
 	filter := &product.SearchFilter{
 		Keyword:  c.Query("keyword"),
 		Category: c.Query("category"),
-		Location: c.Query("location"),
+		SortBy:   c.Query("sort"),
+	}
+
+	if userID, exists := c.Get("userID"); exists {
+		filter.UserID = userID.(uint)
 	}
 
 	if minPrice := c.Query("min_price"); minPrice != "" {
@@ -109,30 +122,92 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 		}
 	}
 
-	if limit := c.Query("limit"); limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil {
-			filter.Limit = l
+	if minDiscount := c.Query("min_discount"); minDiscount != "" {
+		d, err := strconv.ParseFloat(minDiscount, 64)
+		if err != nil {
+			_ = c.Error(product.ErrInvalidFilter.WithMessage("min_discount must be a number"))
+			return
 		}
-	} else {
-		filter.Limit = 20
+		filter.MinDiscount = d
 	}
 
-	if offset := c.Query("offset"); offset != "" {
-		if o, err := strconv.Atoi(offset); err == nil {
-			filter.Offset = o
+	if expiringWithin := c.Query("expiring_within"); expiringWithin != "" {
+		hours, err := strconv.Atoi(expiringWithin)
+		if err != nil {
+			_ = c.Error(product.ErrInvalidFilter.WithMessage("expiring_within must be a number of hours"))
+			return
 		}
+		filter.ExpiringWithin = time.Duration(hours) * time.Hour
+	}
+
+	inStock, err := boolQuery(c, "in_stock")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	filter.InStock = inStock != nil && *inStock
+
+	// Only verified merchants are listed now, so verified is still checked
+	// for old clients but changes nothing
+	if _, err := boolQuery(c, "verified"); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	lat, lng := c.Query("lat"), c.Query("lng")
+	if lat != "" || lng != "" {
+		latitude, latErr := strconv.ParseFloat(lat, 64)
+		longitude, lngErr := strconv.ParseFloat(lng, 64)
+		if latErr != nil || lngErr != nil {
+			_ = c.Error(product.ErrInvalidCoordinates)
+			return
+		}
+		filter.Near = &product.GeoPoint{Latitude: latitude, Longitude: longitude}
+	}
+
+	if radius := c.Query("radius"); radius != "" {
+		r, err := strconv.ParseFloat(radius, 64)
+		if err != nil {
+			_ = c.Error(product.ErrInvalidRadius)
+			return
+		}
+		filter.RadiusKm = r
+	}
+
+	if locationID := c.Query("location_id"); locationID != "" {
+		id, err := strconv.ParseUint(locationID, 10, 32)
+		if err != nil {
+			_ = c.Error(invalidID("location"))
+			return
+		}
+		filter.LocationID = uint(id)
+	}
+
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
+	filter.Page = page
 
-	products, total, err := h.productService.SearchProducts(filter)
+	result, err := h.productService.SearchProducts(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  products,
-		"total": total,
-	})
+	response := gin.H{
+		"data":        result.Products,
+		"next_cursor": result.NextCursor,
+	}
+	if result.Total != nil {
+		response["total"] = *result.Total
+	}
+	if result.Facets != nil {
+		response["facets"] = result.Facets
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // UpdateProduct updates a product (merchant only)
@@ -146,26 +221,25 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 // @Success 200
 // @Router /api/merchant/products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
-	merchantID, exists := c.Get("merchantID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "merchant not authenticated"})
+	merchantID, staffID, ok := merchantStaff(c)
+	if !ok {
 		return
 	}
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product id"})
+		_ = c.Error(invalidID("product"))
 		return
 	}
 
 	var req product.UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 
-	if err := h.productService.UpdateProduct(uint(id), merchantID.(uint), &req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.productService.UpdateProduct(uint(id), merchantID, staffID, &req); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -182,43 +256,143 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	merchantID, exists := c.Get("merchantID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "merchant not authenticated"})
+		_ = c.Error(errMerchantUnauthenticated)
 		return
 	}
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product id"})
+		_ = c.Error(invalidID("product"))
 		return
 	}
 
 	if err := h.productService.DeleteProduct(uint(id), merchantID.(uint)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "product deleted successfully"})
 }
 
-// GetMerchantProducts gets all products for a merchant
+// GetMerchantProducts gets a page of products for a merchant
 // @Summary Get merchant's products
 // @Tags products
 // @Security BearerAuth
 // @Produce json
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {array} product.Product
 // @Router /api/merchant/products [get]
 func (h *ProductHandler) GetMerchantProducts(c *gin.Context) {
 	merchantID, exists := c.Get("merchantID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "merchant not authenticated"})
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	products, next, err := h.productService.GetMerchantProducts(merchantID.(uint), page)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": products, "next_cursor": next})
+}
+
+// GetMarkdownRules gets the markdown rules of the merchant
+// @Summary Get markdown rules
+// @Tags products
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} product.MarkdownRule
+// @Router /api/merchant/markdown-rules [get]
+func (h *ProductHandler) GetMarkdownRules(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	rules, err := h.productService.GetMarkdownRules(merchantID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// SetMarkdownRules replaces the markdown rules of the merchant
+// @Summary Replace markdown rules
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body product.SetMarkdownRulesRequest true "Markdown rules"
+// @Success 200 {array} product.MarkdownRule
+// @Router /api/merchant/markdown-rules [put]
+func (h *ProductHandler) SetMarkdownRules(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	var req product.SetMarkdownRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	rules, err := h.productService.SetMarkdownRules(merchantID.(uint), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// GetPriceHistory gets a page of the price changes of a product, newest first
+// @Summary Get product price history
+// @Tags products
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param limit query int false "Page size" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {array} product.PriceChange
+// @Router /api/merchant/products/{id}/price-history [get]
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("product"))
+		return
+	}
+
+	page, err := pageFromQuery(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	products, err := h.productService.GetMerchantProducts(merchantID.(uint))
+	changes, next, err := h.productService.GetPriceHistory(uint(id), merchantID.(uint), page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": products})
+	c.JSON(http.StatusOK, gin.H{"data": changes, "next_cursor": next})
 }
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/media"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUploadBody caps the multipart body of an image upload, leaving room for
+// the form fields around the file
+const maxUploadBody = product.MaxImageBytes + 1<<20
+
+type ProductImageHandler struct {
+	imageService product.ImageService
+}
+
+// NewProductImageHandler creates a new product image handler
+func NewProductImageHandler(imageService product.ImageService) *ProductImageHandler {
+	return &ProductImageHandler{imageService: imageService}
+}
+
+// UploadImage uploads an image of a product (merchant only)
+// @Summary Upload a product image
+// @Tags products
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param file formData file true "JPEG, PNG or GIF image, at most 5 MB"
+// @Param alt_text formData string false "Alternative text"
+// @Success 201 {object} product.Image
+// @Router /api/merchant/products/{id}/images [post]
+func (h *ProductImageHandler) UploadImage(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("product"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBody)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			_ = c.Error(media.ErrFileTooLarge)
+			return
+		}
+		_ = c.Error(media.ErrFileRequired)
+		return
+	}
+	if header.Size > product.MaxImageBytes {
+		_ = c.Error(media.ErrFileTooLarge)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, product.MaxImageBytes+1))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	upload := &product.ImageUpload{
+		Data:    data,
+		AltText: c.PostForm("alt_text"),
+	}
+	image, err := h.imageService.AddImage(uint(productID), merchantID.(uint), upload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": image})
+}
+
+// UpdateImage updates the alt text of a product image (merchant only)
+// @Summary Update a product image
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Param request body product.UpdateImageRequest true "Image details"
+// @Success 200 {object} product.Image
+// @Router /api/merchant/products/{id}/images/{imageId} [put]
+func (h *ProductImageHandler) UpdateImage(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	productID, imageID, ok := imagePathIDs(c)
+	if !ok {
+		return
+	}
+
+	var req product.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	image, err := h.imageService.UpdateImage(productID, imageID, merchantID.(uint), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": image})
+}
+
+// DeleteImage deletes a product image (merchant only)
+// @Summary Delete a product image
+// @Tags products
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} map[string]string
+// @Router /api/merchant/products/{id}/images/{imageId} [delete]
+func (h *ProductImageHandler) DeleteImage(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	productID, imageID, ok := imagePathIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.imageService.DeleteImage(productID, imageID, merchantID.(uint)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "image deleted successfully"})
+}
+
+// ReorderImages sets the order of the images of a product (merchant only)
+// @Summary Reorder product images
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body product.ReorderImagesRequest true "All image IDs, cover first"
+// @Success 200 {array} product.Image
+// @Router /api/merchant/products/{id}/images [put]
+func (h *ProductImageHandler) ReorderImages(c *gin.Context) {
+	merchantID, exists := c.Get("merchantID")
+	if !exists {
+		_ = c.Error(errMerchantUnauthenticated)
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("product"))
+		return
+	}
+
+	var req product.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	images, err := h.imageService.ReorderImages(uint(productID), merchantID.(uint), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": images})
+}
+
+// imagePathIDs reads the product and image IDs of an image route
+func imagePathIDs(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("product"))
+		return 0, 0, false
+	}
+
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		_ = c.Error(invalidID("image"))
+		return 0, 0, false
+	}
+
+	return uint(productID), uint(imageID), true
+}
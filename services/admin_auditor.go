@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
+	"gorm.io/gorm"
+)
+
+type adminAuditor struct {
+	repo admin.Repository
+}
+
+// NewAdminAuditor creates a new admin audit recorder
+func NewAdminAuditor(repo admin.Repository) admin.Auditor {
+	return &adminAuditor{repo: repo}
+}
+
+// Record adds an admin action to the audit log within the transaction tx
+func (a *adminAuditor) Record(tx *gorm.DB, adminID uint, action string, targetType string, targetID uint, reason string) error {
+	return a.repo.WithTrx(tx).CreateAuditLog(&admin.AuditLog{
+		AdminID:    adminID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+	})
+}
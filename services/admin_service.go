@@ -0,0 +1,163 @@
+package services
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/notification"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+)
+
+type adminService struct {
+	db            lib.Database
+	repo          admin.Repository
+	authRepo      auth.Repository
+	merchantRepo  merchant.Repository
+	orderRepo     order.Repository
+	orders        order.Service
+	products      product.Service
+	notifications notification.Service
+	auditor       admin.Auditor
+	logger        lib.Logger
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(
+	db lib.Database,
+	repo admin.Repository,
+	authRepo auth.Repository,
+	merchantRepo merchant.Repository,
+	orderRepo order.Repository,
+	orders order.Service,
+	products product.Service,
+	notifications notification.Service,
+	auditor admin.Auditor,
+	logger lib.Logger,
+) admin.Service {
+	return &adminService{
+		db:            db,
+		repo:          repo,
+		authRepo:      authRepo,
+		merchantRepo:  merchantRepo,
+		orderRepo:     orderRepo,
+		orders:        orders,
+		products:      products,
+		notifications: notifications,
+		auditor:       auditor,
+		logger:        logger,
+	}
+}
+
+// ListUsers gets a page of users matching the filter, newest first
+func (s *adminService) ListUsers(filter *auth.UserFilter, page pagination.Page) ([]auth.User, string, error) {
+	return s.authRepo.FindUsers(filter, page)
+}
+
+// DeactivateUser blocks a user from logging in and ends their sessions
+func (s *adminService) DeactivateUser(adminID uint, userID uint, req *admin.ReasonRequest) (*auth.User, error) {
+	if adminID == userID {
+		return nil, admin.ErrCannotDeactivateSelf
+	}
+
+	user, err := s.authRepo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return user, nil
+	}
+
+	user.IsActive = false
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.authRepo.WithTrx(tx).UpdateUser(user); err != nil {
+			return err
+		}
+		return s.auditor.Record(tx, adminID, admin.ActionUserDeactivate, admin.TargetUser, userID, req.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens of an inactive user are already rejected, this only cleans up
+	if err := s.authRepo.DeleteUserSessions(userID); err != nil {
+		s.logger.Error("deleting sessions of deactivated user failed: ", err)
+	}
+
+	return user, nil
+}
+
+// ActivateUser lets a deactivated user log in again
+func (s *adminService) ActivateUser(adminID uint, userID uint) (*auth.User, error) {
+	user, err := s.authRepo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsActive {
+		return user, nil
+	}
+
+	user.IsActive = true
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.authRepo.WithTrx(tx).UpdateUser(user); err != nil {
+			return err
+		}
+		return s.auditor.Record(tx, adminID, admin.ActionUserActivate, admin.TargetUser, userID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ListMerchants gets a page of merchants matching the filter, newest first
+func (s *adminService) ListMerchants(filter *merchant.Filter, page pagination.Page) ([]merchant.Merchant, string, error) {
+	return s.merchantRepo.FindMerchants(filter, page)
+}
+
+// ListOrders gets a page of orders matching the filter, newest first
+func (s *adminService) ListOrders(filter *order.Filter, page pagination.Page) ([]order.Order, string, error) {
+	return s.orderRepo.FindOrders(filter, page)
+}
+
+// CancelOrder cancels an open order whatever its status and tells the
+// customer
+func (s *adminService) CancelOrder(adminID uint, orderID uint, req *admin.ReasonRequest) (*order.Order, error) {
+	ord, err := s.orders.ForceCancelOrder(orderID, adminID, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.notifications.Notify(ord.UserID, notification.TypeOrderCancelled,
+		"Your order "+ord.OrderCode+" was cancelled",
+		"Reason: "+req.Reason)
+	if err != nil {
+		s.logger.Error("notifying customer of cancelled order failed: ", err)
+	}
+
+	return ord, nil
+}
+
+// UnlistProduct takes a product down from the public listing
+func (s *adminService) UnlistProduct(adminID uint, productID uint, req *admin.ReasonRequest) (*product.Product, error) {
+	return s.products.UnlistProduct(adminID, productID, req.Reason)
+}
+
+// RelistProduct puts an unlisted product back
+func (s *adminService) RelistProduct(adminID uint, productID uint) (*product.Product, error) {
+	return s.products.RelistProduct(adminID, productID)
+}
+
+// GetStats gets an overview of the platform
+func (s *adminService) GetStats() (*admin.Stats, error) {
+	return s.repo.GetStats()
+}
+
+// GetAuditLogs gets a page of the audit log matching the filter, newest first
+func (s *adminService) GetAuditLogs(filter *admin.AuditFilter, page pagination.Page) ([]admin.AuditLog, string, error) {
+	return s.repo.FindAuditLogs(filter, page)
+}
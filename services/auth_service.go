@@ -1,19 +1,32 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
 	"time"
 )
 
+const (
+	accessTokenTTL  = 24 * time.Hour
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
 type authService struct {
-	repo auth.Repository
+	repo   auth.Repository
+	tokens lib.TokenManager
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(repo auth.Repository) auth.Service {
-	return &authService{repo: repo}
+func NewAuthService(repo auth.Repository, tokens lib.TokenManager) auth.Service {
+	return &authService{
+		repo:   repo,
+		tokens: tokens,
+	}
 }
 
 // Register registers a new user
@@ -21,7 +34,7 @@ func (s *authService) Register(req *auth.RegisterRequest) (*auth.User, error) {
 	// Check if user already exists
 	existingUser, _ := s.repo.FindUserByEmail(req.Email)
 	if existingUser != nil {
-		return nil, errors.New("email already registered")
+		return nil, auth.ErrEmailTaken
 	}
 
 	// Hash password
@@ -36,7 +49,7 @@ func (s *authService) Register(req *auth.RegisterRequest) (*auth.User, error) {
 		Password: hashedPassword,
 		Name:     req.Name,
 		Phone:    req.Phone,
-		Role:     "customer",
+		Role:     rbac.RoleCustomer,
 		IsActive: true,
 	}
 
@@ -52,39 +65,89 @@ func (s *authService) Login(req *auth.LoginRequest) (*auth.LoginResponse, error)
 	// Find user by email
 	user, err := s.repo.FindUserByEmail(req.Email)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, auth.ErrInvalidCredentials
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, errors.New("account is inactive")
+		return nil, auth.ErrAccountInactive
 	}
 
 	// Verify password
 	if !utils.CheckPasswordHash(req.Password, user.Password) {
-		return nil, errors.New("invalid credentials")
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	return s.startSession(user, utils.NewTokenID(), nil)
+}
+
+// Refresh rotates a refresh token into a new access/refresh pair. Presenting
+// a refresh token that was already rotated revokes the whole session family.
+func (s *authService) Refresh(refreshToken string) (*auth.LoginResponse, error) {
+	if _, err := s.tokens.Validate(refreshToken); err != nil {
+		return nil, auth.ErrInvalidRefresh
+	}
+
+	session, err := s.repo.FindSessionByRefreshTokenHash(hashSessionToken(refreshToken))
+	if err != nil {
+		return nil, auth.ErrInvalidRefresh
+	}
+
+	// A rotated token coming back means it was stolen or replayed
+	if session.RotatedAt != nil {
+		if err := s.revokeFamily(session); err != nil {
+			return nil, err
+		}
+		return nil, auth.ErrRefreshReused
+	}
+
+	if session.RefreshExpiresAt.Before(time.Now()) {
+		return nil, auth.ErrTokenExpired
+	}
+
+	if !session.User.IsActive {
+		return nil, auth.ErrAccountInactive
+	}
+
+	response, err := s.startSession(&session.User, session.FamilyID, &session.ID)
+	if errors.Is(err, auth.ErrRefreshReused) {
+		if err := s.revokeFamily(session); err != nil {
+			return nil, err
+		}
 	}
+	return response, err
+}
 
+// startSession issues a token pair and stores it as a session of the given
+// family, rotating out the previous session if one is given
+func (s *authService) startSession(user *auth.User, familyID string, previousID *uint) (*auth.LoginResponse, error) {
 	// Generate tokens
-	accessToken, err := utils.GenerateToken(user.ID, user.Email, user.Role, 24*time.Hour)
+	accessToken, err := s.tokens.Generate(user.ID, user.Email, user.Role, accessTokenTTL)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := utils.GenerateToken(user.ID, user.Email, user.Role, 7*24*time.Hour)
+	refreshToken, err := s.tokens.Generate(user.ID, user.Email, user.Role, refreshTokenTTL)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create session
+	now := time.Now()
 	session := &auth.Session{
-		UserID:       user.ID,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresAt:    time.Now().Add(24 * time.Hour),
+		UserID:           user.ID,
+		FamilyID:         familyID,
+		AccessTokenHash:  hashSessionToken(accessToken),
+		RefreshTokenHash: hashSessionToken(refreshToken),
+		ExpiresAt:        now.Add(accessTokenTTL),
+		RefreshExpiresAt: now.Add(refreshTokenTTL),
 	}
 
-	if err := s.repo.CreateSession(session); err != nil {
+	if previousID != nil {
+		err = s.repo.RotateSession(*previousID, session)
+	} else {
+		err = s.repo.CreateSession(session)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -95,9 +158,29 @@ func (s *authService) Login(req *auth.LoginRequest) (*auth.LoginResponse, error)
 	}, nil
 }
 
+// hashSessionToken gives the stored form of an access or refresh token.
+// Signed tokens are too long for a unique index, their digest is not.
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// revokeFamily deletes every session rotated from the same login as session
+func (s *authService) revokeFamily(session *auth.Session) error {
+	if session.FamilyID == "" {
+		return s.repo.DeleteUserSessions(session.UserID)
+	}
+	return s.repo.DeleteSessionFamily(session.FamilyID)
+}
+
 // Logout logs out a user
 func (s *authService) Logout(token string) error {
-	return s.repo.DeleteSession(token)
+	return s.repo.DeleteSessionByTokenHash(hashSessionToken(token))
+}
+
+// LogoutAll revokes every session of a user
+func (s *authService) LogoutAll(userID uint) error {
+	return s.repo.DeleteUserSessions(userID)
 }
 
 // GetUserByID gets a user by ID
@@ -110,17 +193,32 @@ func (s *authService) UpdateProfile(user *auth.User) error {
 	return s.repo.UpdateUser(user)
 }
 
-// ValidateToken validates a token and returns the user
+// ValidateToken validates a token against its live session and returns the user
 func (s *authService) ValidateToken(token string) (*auth.User, error) {
-	session, err := s.repo.FindSessionByToken(token)
+	// Check signature, expiry, issuer and audience
+	if _, err := s.tokens.Validate(token); err != nil {
+		return nil, auth.ErrInvalidToken
+	}
+
+	session, err := s.repo.FindSessionByTokenHash(hashSessionToken(token))
 	if err != nil {
 		return nil, err
 	}
 
+	// Rotated sessions only live on to detect refresh token reuse
+	if session.RotatedAt != nil {
+		return nil, auth.ErrSessionNotFound
+	}
+
 	// Check if token is expired
 	if session.ExpiresAt.Before(time.Now()) {
-		s.repo.DeleteSession(token)
-		return nil, errors.New("token expired")
+		s.repo.DeleteSessionByTokenHash(hashSessionToken(token))
+		return nil, auth.ErrTokenExpired
+	}
+
+	// Deactivated accounts lose access right away
+	if !session.User.IsActive {
+		return nil, auth.ErrAccountInactive
 	}
 
 	return &session.User, nil
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/category"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+	"gorm.io/gorm"
+)
+
+// slugPattern matches valid category slugs
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type categoryService struct {
+	db      lib.Database
+	repo    category.Repository
+	auditor admin.Auditor
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(db lib.Database, repo category.Repository, auditor admin.Auditor) category.Service {
+	return &categoryService{db: db, repo: repo, auditor: auditor}
+}
+
+// GetTree gets the category tree. Inactive categories and their
+// subcategories are left out unless includeInactive is set.
+func (s *categoryService) GetTree(includeInactive bool) ([]category.Category, error) {
+	categories, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]category.Category)
+	var roots []category.Category
+	for _, cat := range categories {
+		if !cat.IsActive && !includeInactive {
+			continue
+		}
+		if cat.ParentID == nil {
+			roots = append(roots, cat)
+			continue
+		}
+		children[*cat.ParentID] = append(children[*cat.ParentID], cat)
+	}
+
+	var attach func(nodes []category.Category) []category.Category
+	attach = func(nodes []category.Category) []category.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	tree := attach(roots)
+	if tree == nil {
+		tree = []category.Category{}
+	}
+	return tree, nil
+}
+
+// CreateCategory creates a category. The slug defaults to the folded
+// Vietnamese name.
+func (s *categoryService) CreateCategory(adminID uint, req *category.CreateCategoryRequest) (*category.Category, error) {
+	slug := req.Slug
+	if slug == "" {
+		slug = utils.Slugify(req.NameVi)
+	}
+	if err := s.checkSlug(slug, 0); err != nil {
+		return nil, err
+	}
+
+	parentID := req.ParentID
+	if parentID != nil && *parentID == 0 {
+		parentID = nil
+	}
+	if parentID != nil {
+		if _, err := s.repo.FindByID(*parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	cat := &category.Category{
+		ParentID: parentID,
+		Slug:     slug,
+		NameVi:   req.NameVi,
+		NameEn:   req.NameEn,
+		Icon:     req.Icon,
+		Position: req.Position,
+		IsActive: true,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.WithTrx(tx).Create(cat); err != nil {
+			return err
+		}
+		return s.auditor.Record(tx, adminID, admin.ActionCategoryCreate, admin.TargetCategory, cat.ID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cat, nil
+}
+
+// UpdateCategory updates a category. A new slug is carried over to its
+// products by the database.
+func (s *categoryService) UpdateCategory(adminID uint, id uint, req *category.UpdateCategoryRequest) (*category.Category, error) {
+	cat, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Slug != nil && *req.Slug != cat.Slug {
+		if err := s.checkSlug(*req.Slug, cat.ID); err != nil {
+			return nil, err
+		}
+		cat.Slug = *req.Slug
+	}
+	if req.ParentID != nil {
+		if err := s.checkParent(cat.ID, *req.ParentID); err != nil {
+			return nil, err
+		}
+		cat.ParentID = nil
+		if *req.ParentID != 0 {
+			cat.ParentID = req.ParentID
+		}
+	}
+	if req.NameVi != nil {
+		cat.NameVi = *req.NameVi
+	}
+	if req.NameEn != nil {
+		cat.NameEn = *req.NameEn
+	}
+	if req.Icon != nil {
+		cat.Icon = *req.Icon
+	}
+	if req.Position != nil {
+		cat.Position = *req.Position
+	}
+	if req.IsActive != nil {
+		cat.IsActive = *req.IsActive
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.WithTrx(tx).Update(cat); err != nil {
+			return err
+		}
+		return s.auditor.Record(tx, adminID, admin.ActionCategoryUpdate, admin.TargetCategory, cat.ID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cat, nil
+}
+
+// DeleteCategory deletes a category without subcategories or products
+func (s *categoryService) DeleteCategory(adminID uint, id uint) error {
+	cat, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.repo.FindAll()
+	if err != nil {
+		return err
+	}
+	for _, other := range categories {
+		if other.ParentID != nil && *other.ParentID == cat.ID {
+			return category.ErrCategoryInUse
+		}
+	}
+
+	count, err := s.repo.CountProducts(cat.Slug)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return category.ErrCategoryInUse
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.WithTrx(tx).Delete(cat.ID); err != nil {
+			return err
+		}
+		return s.auditor.Record(tx, adminID, admin.ActionCategoryDelete, admin.TargetCategory, cat.ID, "")
+	})
+}
+
+// CheckAssignable verifies that products can be put in the category: it
+// must exist and be active along with all its parents
+func (s *categoryService) CheckAssignable(slug string) error {
+	categories, err := s.repo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uint]*category.Category, len(categories))
+	var cat *category.Category
+	for i := range categories {
+		byID[categories[i].ID] = &categories[i]
+		if categories[i].Slug == slug {
+			cat = &categories[i]
+		}
+	}
+
+	for cat != nil {
+		if !cat.IsActive {
+			return category.ErrInvalidCategory
+		}
+		if cat.ParentID == nil {
+			return nil
+		}
+		cat = byID[*cat.ParentID]
+	}
+	return category.ErrInvalidCategory
+}
+
+// SubtreeSlugs gives the slug of a category followed by the slugs of all
+// its subcategories
+func (s *categoryService) SubtreeSlugs(slug string) ([]string, error) {
+	root, err := s.repo.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[uint][]category.Category)
+	for _, cat := range categories {
+		if cat.ParentID != nil {
+			children[*cat.ParentID] = append(children[*cat.ParentID], cat)
+		}
+	}
+
+	slugs := []string{root.Slug}
+	queue := []uint{root.ID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			slugs = append(slugs, child.Slug)
+			queue = append(queue, child.ID)
+		}
+	}
+
+	return slugs, nil
+}
+
+// checkSlug validates a slug and verifies no other category uses it
+func (s *categoryService) checkSlug(slug string, id uint) error {
+	if !slugPattern.MatchString(slug) {
+		return category.ErrInvalidSlug
+	}
+
+	existing, err := s.repo.FindBySlug(slug)
+	if errors.Is(err, category.ErrCategoryNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != id {
+		return category.ErrSlugTaken
+	}
+	return nil
+}
+
+// checkParent verifies that parentID exists and is not the category itself
+// or one of its subcategories. A zero parentID means the top level.
+func (s *categoryService) checkParent(id uint, parentID uint) error {
+	if parentID == 0 {
+		return nil
+	}
+
+	for next := &parentID; next != nil; {
+		if *next == id {
+			return category.ErrInvalidParent
+		}
+		parent, err := s.repo.FindByID(*next)
+		if err != nil {
+			return err
+		}
+		next = parent.ParentID
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/category"
+	"gorm.io/gorm"
+)
+
+// memoryCategoryRepo keeps categories in memory
+type memoryCategoryRepo struct {
+	category.Repository
+
+	created []*category.Category
+}
+
+func (r *memoryCategoryRepo) WithTrx(*gorm.DB) category.Repository { return r }
+
+func (r *memoryCategoryRepo) FindBySlug(string) (*category.Category, error) {
+	return nil, category.ErrCategoryNotFound
+}
+
+func (r *memoryCategoryRepo) Create(cat *category.Category) error {
+	cat.ID = uint(len(r.created) + 1)
+	r.created = append(r.created, cat)
+	return nil
+}
+
+// failingAuditor cannot write the audit log
+type failingAuditor struct{ err error }
+
+func (a failingAuditor) Record(*gorm.DB, uint, string, string, uint, string) error { return a.err }
+
+func TestCreateCategoryFailsWithoutAuditLog(t *testing.T) {
+	errAudit := errors.New("audit log unavailable")
+	svc := NewCategoryService(memoryDatabase(t, nil), &memoryCategoryRepo{}, failingAuditor{errAudit})
+
+	_, err := svc.CreateCategory(1, &category.CreateCategoryRequest{NameVi: "Bánh mì"})
+	if !errors.Is(err, errAudit) {
+		t.Errorf("err = %v, want %v", err, errAudit)
+	}
+}
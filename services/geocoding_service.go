@@ -0,0 +1,64 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/geocoding"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+)
+
+// geocodeLimit is the number of suggestions returned for a query
+const geocodeLimit = 5
+
+type geocodingService struct {
+	provider geocoding.Provider
+	logger   lib.Logger
+}
+
+// NewGeocodingService creates a new geocoding service
+func NewGeocodingService(provider geocoding.Provider, logger lib.Logger) geocoding.Service {
+	return &geocodingService{
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// Search returns the places matching a free-text query, best match first
+func (s *geocodingService) Search(query string) ([]geocoding.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, geocoding.ErrQueryRequired
+	}
+
+	results, err := s.provider.Geocode(query, geocodeLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, geocoding.ErrNoMatch
+	}
+
+	return results, nil
+}
+
+// FillCoordinates geocodes the address when no coordinates were given.
+// Geocoding is best effort: failures are logged and the coordinates are left
+// unset so the address can still be saved.
+func (s *geocodingService) FillCoordinates(address string, latitude *float64, longitude *float64) {
+	if *latitude != 0 || *longitude != 0 || strings.TrimSpace(address) == "" {
+		return
+	}
+
+	results, err := s.provider.Geocode(address, 1)
+	if err != nil {
+		s.logger.Warn("geocoding failed for address ", address, ": ", err)
+		return
+	}
+	if len(results) == 0 {
+		s.logger.Info("no geocoding match for address ", address)
+		return
+	}
+
+	*latitude = results[0].Latitude
+	*longitude = results[0].Longitude
+}
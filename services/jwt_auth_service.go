@@ -2,8 +2,9 @@ package services
 
 import (
 	"errors"
+	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/models"
@@ -11,26 +12,25 @@ import (
 
 // JWTAuthService service relating to authorization
 type JWTAuthService struct {
-	env    lib.Env
+	tokens lib.TokenManager
 	logger lib.Logger
 }
 
 // NewJWTAuthService creates a new auth service
-func NewJWTAuthService(env lib.Env, logger lib.Logger) domains.AuthService {
+func NewJWTAuthService(tokens lib.TokenManager, logger lib.Logger) domains.AuthService {
 	return JWTAuthService{
-		env:    env,
+		tokens: tokens,
 		logger: logger,
 	}
 }
 
 // Authorize authorizes the generated token
 func (s JWTAuthService) Authorize(tokenString string) (bool, error) {
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		return []byte(s.env.JWTSecret), nil
-	})
-	if token.Valid {
+	_, err := s.tokens.Validate(tokenString)
+	if err == nil {
 		return true, nil
-	} else if ve, ok := err.(*jwt.ValidationError); ok {
+	}
+	if ve, ok := err.(*jwt.ValidationError); ok {
 		if ve.Errors&jwt.ValidationErrorMalformed != 0 {
 			return false, errors.New("token malformed")
 		}
@@ -43,16 +43,14 @@ func (s JWTAuthService) Authorize(tokenString string) (bool, error) {
 
 // CreateToken creates jwt auth token
 func (s JWTAuthService) CreateToken(user models.User) string {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":    user.ID,
-		"name":  user.Name,
-		"email": *user.Email,
-	})
-
-	tokenString, err := token.SignedString([]byte(s.env.JWTSecret))
+	email := ""
+	if user.Email != nil {
+		email = *user.Email
+	}
 
+	tokenString, err := s.tokens.Generate(user.ID, email, "", 24*time.Hour)
 	if err != nil {
-		s.logger.Error("JWT validation failed: ", err)
+		s.logger.Error("JWT signing failed: ", err)
 	}
 
 	return tokenString
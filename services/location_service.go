@@ -1,20 +1,29 @@
 package services
 
 import (
-	"errors"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/geocoding"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/location"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"gorm.io/gorm"
 )
 
 type locationService struct {
-	repo location.Repository
+	db       lib.Database
+	repo     location.Repository
+	geocoder geocoding.Service
 }
 
 // NewLocationService creates a new location service
-func NewLocationService(repo location.Repository) location.Service {
-	return &locationService{repo: repo}
+func NewLocationService(db lib.Database, repo location.Repository, geocoder geocoding.Service) location.Service {
+	return &locationService{
+		db:       db,
+		repo:     repo,
+		geocoder: geocoder,
+	}
 }
 
-// AddLocation adds a new location for a user
+// AddLocation adds a new location for a user. The first location of a user
+// always becomes the default.
 func (s *locationService) AddLocation(userID uint, req *location.AddLocationRequest) (*location.Location, error) {
 	loc := &location.Location{
 		UserID:    userID,
@@ -24,14 +33,31 @@ func (s *locationService) AddLocation(userID uint, req *location.AddLocationRequ
 		Label:     req.Label,
 		IsDefault: req.IsDefault,
 	}
+	s.geocoder.FillCoordinates(loc.Address, &loc.Latitude, &loc.Longitude)
 
-	if err := s.repo.Create(loc); err != nil {
-		return nil, err
-	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTrx(tx)
+
+		existing, err := repo.LockUserLocations(userID)
+		if err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			loc.IsDefault = true
+		}
+
+		if err := repo.Create(loc); err != nil {
+			return err
+		}
 
-	// If this location is set as default, update others
-	if req.IsDefault {
-		s.repo.SetDefaultLocation(userID, loc.ID)
+		// If this location is set as default, update others
+		if loc.IsDefault {
+			return repo.SetDefaultLocation(userID, loc.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return loc, nil
@@ -42,22 +68,129 @@ func (s *locationService) GetUserLocations(userID uint) ([]location.Location, er
 	return s.repo.FindByUserID(userID)
 }
 
+// UpdateLocation updates a location owned by the user
+func (s *locationService) UpdateLocation(userID uint, locationID uint, req *location.UpdateLocationRequest) (*location.Location, error) {
+	var loc *location.Location
+
+	// A new address without coordinates is geocoded before the transaction
+	// so the provider is not called while the user's locations are locked
+	regeocode := req.Address != nil && req.Latitude == nil && req.Longitude == nil
+	var latitude, longitude float64
+	if regeocode {
+		s.geocoder.FillCoordinates(*req.Address, &latitude, &longitude)
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTrx(tx)
+
+		var err error
+		loc, err = s.findUserLocation(repo, userID, locationID)
+		if err != nil {
+			return err
+		}
+
+		if req.Address != nil {
+			loc.Address = *req.Address
+		}
+		if regeocode {
+			loc.Latitude, loc.Longitude = latitude, longitude
+		}
+		if req.Latitude != nil {
+			loc.Latitude = *req.Latitude
+		}
+		if req.Longitude != nil {
+			loc.Longitude = *req.Longitude
+		}
+		if req.Label != nil {
+			loc.Label = *req.Label
+		}
+
+		makeDefault := false
+		if req.IsDefault != nil {
+			// A user must always keep exactly one default location
+			if !*req.IsDefault && loc.IsDefault {
+				return location.ErrDefaultRequired
+			}
+			makeDefault = *req.IsDefault && !loc.IsDefault
+		}
+
+		if err := repo.Update(loc); err != nil {
+			return err
+		}
+
+		if makeDefault {
+			if err := repo.SetDefaultLocation(userID, loc.ID); err != nil {
+				return err
+			}
+			loc.IsDefault = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loc, nil
+}
+
 // SetDefaultLocation sets a location as default
 func (s *locationService) SetDefaultLocation(userID uint, locationID uint) error {
-	return s.repo.SetDefaultLocation(userID, locationID)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTrx(tx)
+
+		if _, err := s.findUserLocation(repo, userID, locationID); err != nil {
+			return err
+		}
+
+		return repo.SetDefaultLocation(userID, locationID)
+	})
 }
 
-// DeleteLocation deletes a location
+// DeleteLocation deletes a location. When the default location is deleted,
+// the most recent remaining location becomes the default.
 func (s *locationService) DeleteLocation(userID uint, locationID uint) error {
-	// Get location to verify ownership
-	loc, err := s.repo.FindByID(locationID)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTrx(tx)
+
+		loc, err := s.findUserLocation(repo, userID, locationID)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.Delete(locationID); err != nil {
+			return err
+		}
+
+		if !loc.IsDefault {
+			return nil
+		}
+
+		remaining, err := repo.FindByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+		return repo.SetDefaultLocation(userID, remaining[0].ID)
+	})
+}
+
+// findUserLocation locks the user's locations and returns the requested one,
+// verifying ownership
+func (s *locationService) findUserLocation(repo location.Repository, userID uint, locationID uint) (*location.Location, error) {
+	if _, err := repo.LockUserLocations(userID); err != nil {
+		return nil, err
+	}
+
+	loc, err := repo.FindByID(locationID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if loc.UserID != userID {
-		return errors.New("unauthorized")
+		return nil, location.ErrNotLocationOwner
 	}
 
-	return s.repo.Delete(locationID)
+	return loc, nil
 }
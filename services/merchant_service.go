@@ -1,31 +1,42 @@
 package services
 
 import (
-	"errors"
+	"time"
+
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/geocoding"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+	"gorm.io/gorm"
 )
 
 type merchantService struct {
+	db       lib.Database
 	repo     merchant.Repository
 	authRepo auth.Repository
+	geocoder geocoding.Service
 }
 
 // NewMerchantService creates a new merchant service
-func NewMerchantService(repo merchant.Repository, authRepo auth.Repository) merchant.Service {
+func NewMerchantService(db lib.Database, repo merchant.Repository, authRepo auth.Repository, geocoder geocoding.Service) merchant.Service {
 	return &merchantService{
+		db:       db,
 		repo:     repo,
 		authRepo: authRepo,
+		geocoder: geocoder,
 	}
 }
 
-// RegisterMerchant registers a new merchant
+// RegisterMerchant registers a new merchant. The user account, the shop and
+// its owner membership are created in one transaction, so a failure leaves
+// no half-registered account behind.
 func (s *merchantService) RegisterMerchant(req *merchant.RegisterMerchantRequest) (*merchant.Merchant, error) {
 	// Check if email already exists
 	existingUser, _ := s.authRepo.FindUserByEmail(req.Email)
 	if existingUser != nil {
-		return nil, errors.New("email already registered")
+		return nil, auth.ErrEmailTaken
 	}
 
 	// Hash password
@@ -40,17 +51,12 @@ func (s *merchantService) RegisterMerchant(req *merchant.RegisterMerchantRequest
 		Password: hashedPassword,
 		Name:     req.Name,
 		Phone:    req.Phone,
-		Role:     "merchant",
+		Role:     rbac.RoleMerchant,
 		IsActive: true,
 	}
 
-	if err := s.authRepo.CreateUser(user); err != nil {
-		return nil, err
-	}
-
 	// Create merchant profile
 	merch := &merchant.Merchant{
-		UserID:      user.ID,
 		ShopName:    req.ShopName,
 		ShopAddress: req.ShopAddress,
 		Phone:       req.Phone,
@@ -60,8 +66,31 @@ func (s *merchantService) RegisterMerchant(req *merchant.RegisterMerchantRequest
 		IsVerified:  false,
 		IsActive:    true,
 	}
+	s.geocoder.FillCoordinates(merch.ShopAddress, &merch.Latitude, &merch.Longitude)
 
-	if err := s.repo.Create(merch); err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.authRepo.WithTrx(tx).CreateUser(user); err != nil {
+			return err
+		}
+
+		repo := s.repo.WithTrx(tx)
+		merch.UserID = user.ID
+		if err := repo.Create(merch); err != nil {
+			return err
+		}
+
+		// The owner is the first member of the shop
+		now := time.Now()
+		return repo.CreateMember(&merchant.Member{
+			MerchantID: merch.ID,
+			UserID:     &user.ID,
+			Email:      user.Email,
+			Role:       rbac.RoleOwner,
+			Status:     merchant.MemberActive,
+			AcceptedAt: &now,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -90,8 +119,13 @@ func (s *merchantService) UpdateMerchant(id uint, req *merchant.UpdateMerchantRe
 	if req.ShopName != "" {
 		merch.ShopName = req.ShopName
 	}
-	if req.ShopAddress != "" {
+	if req.ShopAddress != "" && req.ShopAddress != merch.ShopAddress {
 		merch.ShopAddress = req.ShopAddress
+		// Coordinates of the old address no longer apply
+		if req.Latitude == 0 && req.Longitude == 0 {
+			merch.Latitude, merch.Longitude = 0, 0
+			s.geocoder.FillCoordinates(merch.ShopAddress, &merch.Latitude, &merch.Longitude)
+		}
 	}
 	if req.Phone != "" {
 		merch.Phone = req.Phone
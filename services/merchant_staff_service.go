@@ -0,0 +1,264 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/mail"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/notification"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+	"gorm.io/gorm"
+)
+
+type merchantStaffService struct {
+	db            lib.Database
+	repo          merchant.Repository
+	authRepo      auth.Repository
+	notifications notification.Service
+	mailer        mail.Sender
+	appURL        string
+	logger        lib.Logger
+}
+
+// NewMerchantStaffService creates a new merchant staff service
+func NewMerchantStaffService(
+	db lib.Database,
+	repo merchant.Repository,
+	authRepo auth.Repository,
+	notifications notification.Service,
+	mailer mail.Sender,
+	env lib.Env,
+	logger lib.Logger,
+) merchant.StaffService {
+	return &merchantStaffService{
+		db:            db,
+		repo:          repo,
+		authRepo:      authRepo,
+		notifications: notifications,
+		mailer:        mailer,
+		appURL:        strings.TrimRight(env.AppURL, "/"),
+		logger:        logger,
+	}
+}
+
+// GetMembership gets the active membership of a user in the shop they work for
+func (s *merchantStaffService) GetMembership(userID uint) (*merchant.Member, error) {
+	return s.repo.FindActiveMemberByUserID(userID)
+}
+
+// GetMembers gets the members and pending invitations of a shop
+func (s *merchantStaffService) GetMembers(merchantID uint) ([]merchant.Member, error) {
+	return s.repo.FindMembers(merchantID)
+}
+
+// InviteMember invites a user to the shop by email. The email carries the
+// token needed to answer the invitation, so registering an account with the
+// address is not enough to join the shop. The invitation is only kept if the
+// email was sent.
+func (s *merchantStaffService) InviteMember(merchantID uint, ownerID uint, req *merchant.InviteMemberRequest) (*merchant.Member, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	_, err := s.repo.FindOpenMemberByEmail(merchantID, email)
+	if err == nil {
+		return nil, merchant.ErrAlreadyInvited
+	}
+	if !errors.Is(err, merchant.ErrMemberNotFound) {
+		return nil, err
+	}
+
+	merch, err := s.repo.FindByID(merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	token := utils.NewTokenID()
+	expiresAt := time.Now().Add(merchant.InvitationTTL)
+	member := &merchant.Member{
+		MerchantID:          merchantID,
+		Email:               email,
+		Role:                req.Role,
+		Status:              merchant.MemberInvited,
+		InvitedBy:           &ownerID,
+		InvitationTokenHash: hashInvitationToken(token),
+		InvitationExpiresAt: &expiresAt,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.WithTrx(tx).CreateMember(member); err != nil {
+			return err
+		}
+		return s.mailer.Send(s.invitationEmail(merch, member, token))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if user, err := s.authRepo.FindUserByEmail(email); err == nil {
+		s.notify(user.ID, notification.TypeStaffInvitation,
+			"You are invited to "+merch.ShopName,
+			merch.ShopName+" invited you to join as "+req.Role+". Answer it with the link in the invitation email.")
+	}
+
+	return member, nil
+}
+
+// UpdateMember changes the role of a member or pending invitation
+func (s *merchantStaffService) UpdateMember(merchantID uint, memberID uint, req *merchant.UpdateMemberRequest) (*merchant.Member, error) {
+	member, err := s.repo.FindMember(merchantID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == rbac.RoleOwner {
+		return nil, merchant.ErrCannotChangeOwner
+	}
+	if member.Role == req.Role {
+		return member, nil
+	}
+
+	member.Role = req.Role
+	if err := s.repo.UpdateMember(member, member.Status); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RevokeMember removes a member from the shop, or withdraws a pending
+// invitation. The member loses access with their next request.
+func (s *merchantStaffService) RevokeMember(merchantID uint, memberID uint, ownerID uint) error {
+	member, err := s.repo.FindMember(merchantID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Role == rbac.RoleOwner {
+		return merchant.ErrCannotChangeOwner
+	}
+
+	from := member.Status
+	now := time.Now()
+	member.Status = merchant.MemberRevoked
+	member.RevokedAt = &now
+	member.RevokedBy = &ownerID
+
+	return s.repo.UpdateMember(member, from)
+}
+
+// GetInvitations gets the pending invitations sent to the email of a user
+func (s *merchantStaffService) GetInvitations(userID uint) ([]merchant.Member, error) {
+	user, err := s.authRepo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindInvitations(strings.ToLower(user.Email))
+}
+
+// AcceptInvitation makes a user a member of the inviting shop. A user works
+// for a single shop at a time.
+func (s *merchantStaffService) AcceptInvitation(userID uint, invitationID uint, token string) (*merchant.Member, error) {
+	invitation, err := s.invitation(userID, invitationID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.FindActiveMemberByUserID(userID)
+	if err == nil {
+		return nil, merchant.ErrAlreadyMember
+	}
+	if !errors.Is(err, merchant.ErrMemberNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.UserID = &userID
+	invitation.Status = merchant.MemberActive
+	invitation.AcceptedAt = &now
+	invitation.InvitationTokenHash = ""
+	if err := s.repo.UpdateMember(invitation, merchant.MemberInvited); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// DeclineInvitation turns down an invitation
+func (s *merchantStaffService) DeclineInvitation(userID uint, invitationID uint, token string) error {
+	invitation, err := s.invitation(userID, invitationID, token)
+	if err != nil {
+		return err
+	}
+
+	invitation.Status = merchant.MemberDeclined
+	invitation.InvitationTokenHash = ""
+	return s.repo.UpdateMember(invitation, merchant.MemberInvited)
+}
+
+// invitation finds a pending invitation sent to the email of a user and
+// checks the token of the invitation email. The token is cleared once the
+// invitation is answered, so it works only once.
+func (s *merchantStaffService) invitation(userID uint, invitationID uint, token string) (*merchant.Member, error) {
+	user, err := s.authRepo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.repo.FindInvitation(invitationID, strings.ToLower(user.Email))
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashInvitationToken(token)
+	if invitation.InvitationTokenHash == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(invitation.InvitationTokenHash)) != 1 {
+		return nil, merchant.ErrInvitationToken
+	}
+	if invitation.InvitationExpiresAt != nil && time.Now().After(*invitation.InvitationExpiresAt) {
+		return nil, merchant.ErrInvitationExpired
+	}
+
+	return invitation, nil
+}
+
+// invitationEmail writes the email inviting a member, with a link to answer
+// the invitation when the app URL is set
+func (s *merchantStaffService) invitationEmail(merch *merchant.Merchant, member *merchant.Member, token string) mail.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s invited you to join their shop as %s.\n\n", merch.ShopName, member.Role)
+	if s.appURL != "" {
+		fmt.Fprintf(&body, "Open %s/invitations/%d?token=%s to accept or decline.\n",
+			s.appURL, member.ID, url.QueryEscape(token))
+	} else {
+		fmt.Fprintf(&body, "Sign in with this email address and answer invitation %d with the code %s.\n",
+			member.ID, token)
+	}
+	fmt.Fprintf(&body, "The invitation expires on %s.\n", member.InvitationExpiresAt.Format("2006-01-02 15:04 MST"))
+
+	return mail.Message{
+		To:      member.Email,
+		Subject: "You are invited to " + merch.ShopName,
+		Body:    body.String(),
+	}
+}
+
+// hashInvitationToken gives the stored form of an invitation token
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// notify tells a user about their membership. It is a courtesy on top of
+// the invitation email and GetInvitations, so errors do not fail the invite.
+func (s *merchantStaffService) notify(userID uint, notificationType string, title string, body string) {
+	if err := s.notifications.Notify(userID, notificationType, title, body); err != nil {
+		s.logger.Error("notifying shop member failed: ", err)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/mail"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/rbac"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"gorm.io/gorm"
+)
+
+// memberRepo keeps the members of one shop in memory
+type memberRepo struct {
+	merchant.Repository
+
+	mu      sync.Mutex
+	shop    merchant.Merchant
+	members []merchant.Member
+}
+
+func (r *memberRepo) WithTrx(*gorm.DB) merchant.Repository { return r }
+
+func (r *memberRepo) FindByID(id uint) (*merchant.Merchant, error) {
+	if id != r.shop.ID {
+		return nil, merchant.ErrMerchantNotFound
+	}
+	shop := r.shop
+	return &shop, nil
+}
+
+func (r *memberRepo) FindOpenMemberByEmail(merchantID uint, email string) (*merchant.Member, error) {
+	return nil, merchant.ErrMemberNotFound
+}
+
+func (r *memberRepo) CreateMember(member *merchant.Member) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	member.ID = uint(len(r.members) + 1)
+	r.members = append(r.members, *member)
+	return nil
+}
+
+func (r *memberRepo) FindInvitation(id uint, email string) (*merchant.Member, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, member := range r.members {
+		if member.ID == id && member.Email == email && member.Status == merchant.MemberInvited {
+			return &member, nil
+		}
+	}
+	return nil, merchant.ErrInvitationNotFound
+}
+
+func (r *memberRepo) FindActiveMemberByUserID(userID uint) (*merchant.Member, error) {
+	return nil, merchant.ErrMemberNotFound
+}
+
+func (r *memberRepo) UpdateMember(member *merchant.Member, from string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.members {
+		if r.members[i].ID == member.ID && r.members[i].Status == from {
+			r.members[i] = *member
+			return nil
+		}
+	}
+	return merchant.ErrMemberChanged
+}
+
+// userRepo finds users by ID
+type userRepo struct {
+	auth.Repository
+	users map[uint]auth.User
+}
+
+func (r *userRepo) FindUserByID(id uint) (*auth.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, auth.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (r *userRepo) FindUserByEmail(email string) (*auth.User, error) {
+	return nil, auth.ErrUserNotFound
+}
+
+// outbox keeps the sent emails
+type outbox struct {
+	messages []mail.Message
+}
+
+func (o *outbox) Send(message mail.Message) error {
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+var invitationLink = regexp.MustCompile(`/invitations/\d+\?token=(\S+)`)
+
+func TestInvitationRequiresEmailedToken(t *testing.T) {
+	members := &memberRepo{shop: merchant.Merchant{ID: 3, ShopName: "Bakery"}}
+	users := &userRepo{users: map[uint]auth.User{9: {ID: 9, Email: "cashier@example.com"}}}
+	sent := &outbox{}
+	svc := NewMerchantStaffService(memoryDatabase(t, nil), members, users, nil, sent,
+		lib.Env{AppURL: "https://smartket.example/"}, lib.Logger{})
+
+	invitation, err := svc.InviteMember(3, 1, &merchant.InviteMemberRequest{Email: "Cashier@example.com", Role: rbac.RoleCashier})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(sent.messages); n != 1 {
+		t.Fatalf("sent emails = %d, want 1", n)
+	}
+	if to := sent.messages[0].To; to != "cashier@example.com" {
+		t.Errorf("email sent to %q, want cashier@example.com", to)
+	}
+	match := invitationLink.FindStringSubmatch(sent.messages[0].Body)
+	if match == nil {
+		t.Fatalf("no invitation link in %q", sent.messages[0].Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Registering the invited address alone is not enough
+	for _, guess := range []string{"", "0123456789abcdef0123456789abcdef"} {
+		if _, err := svc.AcceptInvitation(9, invitation.ID, guess); !errors.Is(err, merchant.ErrInvitationToken) {
+			t.Errorf("token %q: err = %v, want %v", guess, err, merchant.ErrInvitationToken)
+		}
+	}
+
+	member, err := svc.AcceptInvitation(9, invitation.ID, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member.Status != merchant.MemberActive || member.UserID == nil || *member.UserID != 9 {
+		t.Errorf("member = %+v, want active member of user 9", member)
+	}
+
+	// The token works once
+	if _, err := svc.AcceptInvitation(9, invitation.ID, token); !errors.Is(err, merchant.ErrInvitationNotFound) {
+		t.Errorf("second accept: err = %v, want %v", err, merchant.ErrInvitationNotFound)
+	}
+}
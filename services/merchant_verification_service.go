@@ -0,0 +1,256 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/media"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/notification"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+	"gorm.io/gorm"
+)
+
+// documentExtensions maps the accepted KYC document types to their file
+// extension
+var documentExtensions = map[string]string{
+	"application/pdf": "pdf",
+	"image/jpeg":      "jpg",
+	"image/png":       "png",
+}
+
+type merchantVerificationService struct {
+	db            lib.Database
+	repo          merchant.Repository
+	storage       media.Storage
+	notifications notification.Service
+	auditor       admin.Auditor
+	logger        lib.Logger
+}
+
+// NewMerchantVerificationService creates a new merchant verification service
+func NewMerchantVerificationService(
+	db lib.Database,
+	repo merchant.Repository,
+	storage media.Storage,
+	notifications notification.Service,
+	auditor admin.Auditor,
+	logger lib.Logger,
+) merchant.VerificationService {
+	return &merchantVerificationService{
+		db:            db,
+		repo:          repo,
+		storage:       storage,
+		notifications: notifications,
+		auditor:       auditor,
+		logger:        logger,
+	}
+}
+
+// UploadDocument stores a KYC document in private storage
+func (s *merchantVerificationService) UploadDocument(merchantID uint, upload *merchant.DocumentUpload) (*merchant.Document, error) {
+	if !validDocumentType(upload.Type) {
+		return nil, merchant.ErrInvalidDocumentType
+	}
+	if len(upload.Data) == 0 {
+		return nil, media.ErrFileRequired
+	}
+	if len(upload.Data) > merchant.MaxDocumentBytes {
+		return nil, media.ErrFileTooLarge
+	}
+
+	// The declared content type is not trusted, the file is sniffed instead
+	contentType := http.DetectContentType(upload.Data)
+	extension, ok := documentExtensions[contentType]
+	if !ok {
+		return nil, media.ErrUnsupportedFile
+	}
+
+	merch, err := s.repo.FindByID(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if merch.VerificationStatus == merchant.VerificationPending ||
+		merch.VerificationStatus == merchant.VerificationApproved {
+		return nil, merchant.ErrVerificationLocked
+	}
+
+	documents, err := s.repo.FindDocuments(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) >= merchant.MaxDocumentsPerMerchant {
+		return nil, merchant.ErrTooManyDocuments
+	}
+
+	document := &merchant.Document{
+		MerchantID:  merchantID,
+		Type:        upload.Type,
+		FileName:    upload.FileName,
+		ContentType: contentType,
+		Size:        len(upload.Data),
+		StorageKey:  fmt.Sprintf("%skyc/%d/%s.%s", media.PrivatePrefix, merchantID, utils.NewTokenID(), extension),
+	}
+	if err := s.storage.Put(document.StorageKey, upload.Data, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateDocument(document); err != nil {
+		if err := s.storage.Delete(document.StorageKey); err != nil {
+			s.logger.Error("deleting stored document failed: ", err)
+		}
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// GetVerification gets the verification status of a merchant with its
+// documents
+func (s *merchantVerificationService) GetVerification(merchantID uint) (*merchant.Merchant, error) {
+	merch, err := s.repo.FindByID(merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	merch.Documents, err = s.repo.FindDocuments(merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return merch, nil
+}
+
+// SubmitForReview puts a merchant in the review queue once all required
+// documents are uploaded
+func (s *merchantVerificationService) SubmitForReview(merchantID uint) (*merchant.Merchant, error) {
+	merch, err := s.GetVerification(merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := merch.VerificationStatus
+	switch from {
+	case merchant.VerificationPending:
+		return merch, nil
+	case merchant.VerificationApproved:
+		return nil, merchant.ErrCannotSubmitApproved
+	}
+
+	uploaded := make(map[string]bool, len(merch.Documents))
+	for _, document := range merch.Documents {
+		uploaded[document.Type] = true
+	}
+	for _, required := range merchant.RequiredDocuments {
+		if !uploaded[required] {
+			return nil, merchant.ErrDocumentsMissing
+		}
+	}
+
+	now := time.Now()
+	merch.VerificationStatus = merchant.VerificationPending
+	merch.VerificationNote = ""
+	merch.SubmittedAt = &now
+	if err := s.repo.UpdateVerification(merch, from); err != nil {
+		return nil, err
+	}
+
+	return merch, nil
+}
+
+// GetPendingMerchants gets the review queue, first submitted first
+func (s *merchantVerificationService) GetPendingMerchants() ([]merchant.Merchant, error) {
+	return s.repo.FindPendingReview()
+}
+
+// GetDocumentFile gets a KYC document with the content of its file
+func (s *merchantVerificationService) GetDocumentFile(merchantID uint, documentID uint) (*merchant.Document, []byte, error) {
+	document, err := s.repo.FindDocument(merchantID, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := s.storage.Get(document.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return document, data, nil
+}
+
+// Approve verifies a pending merchant, making its products public
+func (s *merchantVerificationService) Approve(merchantID uint, adminID uint) (*merchant.Merchant, error) {
+	merch, err := s.review(merchantID, adminID, merchant.VerificationApproved, "", admin.ActionMerchantApprove)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(merch, notification.TypeMerchantApproved,
+		"Your shop is verified",
+		"Your documents were approved. Your products are now visible to customers.")
+
+	return merch, nil
+}
+
+// Reject sends a pending merchant back with the reason
+func (s *merchantVerificationService) Reject(merchantID uint, adminID uint, req *merchant.RejectMerchantRequest) (*merchant.Merchant, error) {
+	merch, err := s.review(merchantID, adminID, merchant.VerificationRejected, req.Reason, admin.ActionMerchantReject)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(merch, notification.TypeMerchantRejected,
+		"Your shop verification was rejected",
+		"Reason: "+req.Reason+". Please update your documents and submit again.")
+
+	return merch, nil
+}
+
+// review records the decision of an admin on a pending merchant along with
+// its audit log entry
+func (s *merchantVerificationService) review(merchantID uint, adminID uint, status string, note string, action string) (*merchant.Merchant, error) {
+	merch, err := s.repo.FindByID(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if merch.VerificationStatus != merchant.VerificationPending {
+		return nil, merchant.ErrNotPendingReview
+	}
+
+	now := time.Now()
+	merch.VerificationStatus = status
+	merch.IsVerified = status == merchant.VerificationApproved
+	merch.VerificationNote = note
+	merch.ReviewedAt = &now
+	merch.ReviewedBy = &adminID
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.WithTrx(tx).UpdateVerification(merch, merchant.VerificationPending); err != nil {
+			return err
+		}
+		return s.auditor.Record(tx, adminID, action, admin.TargetMerchant, merchantID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return merch, nil
+}
+
+// notify tells the merchant about the review. The verification status on
+// their profile shows the decision either way.
+func (s *merchantVerificationService) notify(merch *merchant.Merchant, notificationType string, title string, body string) {
+	if err := s.notifications.Notify(merch.UserID, notificationType, title, body); err != nil {
+		s.logger.Error("notifying merchant of review failed: ", err)
+	}
+}
+
+// validDocumentType reports whether t is a known KYC document type
+func validDocumentType(t string) bool {
+	switch t {
+	case merchant.DocumentBusinessLicense, merchant.DocumentFoodSafety, merchant.DocumentOther:
+		return true
+	}
+	return false
+}
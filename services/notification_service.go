@@ -0,0 +1,42 @@
+package services
+
+import (
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/notification"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+)
+
+type notificationService struct {
+	repo notification.Repository
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(repo notification.Repository) notification.Service {
+	return &notificationService{repo: repo}
+}
+
+// Notify sends an in-app notification to a user
+func (s *notificationService) Notify(userID uint, notificationType string, title string, body string) error {
+	return s.repo.Create(&notification.Notification{
+		UserID: userID,
+		Type:   notificationType,
+		Title:  title,
+		Body:   body,
+	})
+}
+
+// GetUserNotifications gets a page of a user's notifications, newest first
+func (s *notificationService) GetUserNotifications(userID uint, page pagination.Page) ([]notification.Notification, string, error) {
+	return s.repo.FindByUserID(userID, page)
+}
+
+// MarkRead marks a user's notification as read
+func (s *notificationService) MarkRead(userID uint, id uint) error {
+	found, err := s.repo.MarkRead(userID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notification.ErrNotificationNotFound
+	}
+	return nil
+}
@@ -2,45 +2,97 @@ package services
 
 import (
 	"errors"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/merchant"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+	"gorm.io/gorm"
+	"sort"
 	"time"
 )
 
 type orderService struct {
-	repo        order.Repository
-	productRepo product.Repository
+	db           lib.Database
+	repo         order.Repository
+	productRepo  product.Repository
+	merchantRepo merchant.Repository
+	redeemTokens lib.RedeemTokenManager
+	auditor      admin.Auditor
+	logger       lib.Logger
 }
 
 // NewOrderService creates a new order service
-func NewOrderService(repo order.Repository, productRepo product.Repository) order.Service {
+func NewOrderService(
+	db lib.Database,
+	repo order.Repository,
+	productRepo product.Repository,
+	merchantRepo merchant.Repository,
+	redeemTokens lib.RedeemTokenManager,
+	auditor admin.Auditor,
+	logger lib.Logger,
+) order.Service {
 	return &orderService{
-		repo:        repo,
-		productRepo: productRepo,
+		db:           db,
+		repo:         repo,
+		productRepo:  productRepo,
+		merchantRepo: merchantRepo,
+		redeemTokens: redeemTokens,
+		auditor:      auditor,
+		logger:       logger,
 	}
 }
 
-// CreateOrder creates a new order
+// CreateOrder creates a new order. Stock is reserved item by item inside one
+// transaction, so a failing item rolls back every reservation before it.
 func (s *orderService) CreateOrder(userID uint, req *order.CreateOrderRequest) (*order.Order, error) {
+	var ord *order.Order
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		ord, err = s.placeOrder(tx, userID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ord, nil
+}
+
+// placeOrder reserves stock for the requested items and creates the order
+// using the given transaction
+func (s *orderService) placeOrder(tx *gorm.DB, userID uint, req *order.CreateOrderRequest) (*order.Order, error) {
+	productRepo := s.productRepo.WithTrx(tx)
+
 	var totalAmount float64
 	var orderItems []order.OrderItem
 
-	// Calculate total and prepare order items
+	// Calculate total and reserve stock for each item
 	for _, item := range req.Items {
-		prod, err := s.productRepo.FindByID(item.ProductID)
+		prod, err := productRepo.FindListedByID(item.ProductID)
 		if err != nil {
-			return nil, errors.New("product not found")
-		}
-
-		// Check stock
-		if prod.Stock < item.Quantity {
-			return nil, errors.New("insufficient stock for product: " + prod.Name)
+			return nil, err
 		}
 
 		// Check if product belongs to the specified merchant
 		if prod.MerchantID != req.MerchantID {
-			return nil, errors.New("all products must be from the same merchant")
+			return nil, order.ErrMixedMerchants
+		}
+		if err := checkAvailable(prod, item.Quantity); err != nil {
+			return nil, err
+		}
+
+		// Take the stock only if the product is still for sale with enough
+		// left at this moment
+		reserved, err := productRepo.ReserveStock(prod.ID, item.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			return nil, order.ErrInsufficientStock.WithMessage("insufficient stock for product: " + prod.Name)
 		}
 
 		subtotal := prod.SalePrice * float64(item.Quantity)
@@ -53,12 +105,6 @@ func (s *orderService) CreateOrder(userID uint, req *order.CreateOrderRequest) (
 			Subtotal:    subtotal,
 			ProductName: prod.Name,
 		})
-
-		// Update stock
-		prod.Stock -= item.Quantity
-		if err := s.productRepo.Update(prod); err != nil {
-			return nil, err
-		}
 	}
 
 	// Create order
@@ -67,7 +113,7 @@ func (s *orderService) CreateOrder(userID uint, req *order.CreateOrderRequest) (
 		MerchantID:      req.MerchantID,
 		OrderCode:       utils.GenerateOrderCode(),
 		TotalAmount:     totalAmount,
-		Status:          "pending",
+		Status:          order.StatusPending,
 		PaymentMethod:   req.PaymentMethod,
 		PaymentStatus:   "unpaid",
 		DeliveryAddress: req.DeliveryAddress,
@@ -76,7 +122,7 @@ func (s *orderService) CreateOrder(userID uint, req *order.CreateOrderRequest) (
 		Items:           orderItems,
 	}
 
-	if err := s.repo.CreateOrder(ord); err != nil {
+	if err := s.repo.WithTrx(tx).CreateOrder(ord); err != nil {
 		return nil, err
 	}
 
@@ -93,45 +139,279 @@ func (s *orderService) GetOrderByCode(code string) (*order.Order, error) {
 	return s.repo.FindOrderByCode(code)
 }
 
-// GetUserOrders gets all orders for a user
-func (s *orderService) GetUserOrders(userID uint) ([]order.Order, error) {
-	return s.repo.FindOrdersByUserID(userID)
+// GetUserOrders gets a page of orders for a user
+func (s *orderService) GetUserOrders(userID uint, page pagination.Page) ([]order.Order, string, error) {
+	return s.repo.FindOrdersByUserID(userID, page)
 }
 
-// GetMerchantOrders gets all orders for a merchant
-func (s *orderService) GetMerchantOrders(merchantID uint) ([]order.Order, error) {
-	return s.repo.FindOrdersByMerchantID(merchantID)
+// GetMerchantOrders gets a page of orders for a merchant
+func (s *orderService) GetMerchantOrders(merchantID uint, page pagination.Page) ([]order.Order, string, error) {
+	return s.repo.FindOrdersByMerchantID(merchantID, page)
 }
 
-// RedeemOrder redeems an order (merchant confirms pickup)
-func (s *orderService) RedeemOrder(merchantID uint, orderCode string) error {
-	ord, err := s.repo.FindOrderByCode(orderCode)
+// GetRedeemToken issues the pickup token of a customer's order, shown as a
+// QR code at the counter
+func (s *orderService) GetRedeemToken(userID uint, orderID uint) (*order.RedeemToken, error) {
+	ord, err := s.repo.FindOrderByID(orderID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Check if order belongs to the user
+	if ord.UserID != userID {
+		return nil, order.ErrNotOrderOwner
+	}
+
+	now := time.Now()
+	if !ord.Status.CanTransitionTo(order.StatusCompleted) {
+		return nil, order.ErrNotRedeemable
+	}
+	if ord.IsPickupExpired(now) {
+		return nil, order.ErrPickupExpired
+	}
+
+	expiresAt := now.Add(order.RedeemTokenTTL)
+	return &order.RedeemToken{
+		Token:     s.redeemTokens.Sign(ord.ID, expiresAt),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// RedeemOrder completes an order with the token scanned from the customer's
+// QR code (merchant confirms pickup). The completion is a conditional status
+// update, so a token works once: a second scan, even a concurrent one, fails.
+func (s *orderService) RedeemOrder(merchantID uint, staffID uint, token string) (*order.Order, error) {
+	orderID, err := s.redeemTokens.Verify(token, time.Now())
+	if errors.Is(err, lib.ErrRedeemTokenExpired) {
+		return nil, order.ErrRedeemCodeExpired
+	}
+	if err != nil {
+		return nil, order.ErrInvalidRedeemCode
+	}
+
+	ord, err := s.repo.FindOrderByID(orderID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if order belongs to the merchant
 	if ord.MerchantID != merchantID {
-		return errors.New("unauthorized")
+		return nil, order.ErrNotOrderOwner
 	}
 
 	// Check if order is in correct status
-	if ord.Status != "pending" && ord.Status != "confirmed" && ord.Status != "ready" {
-		return errors.New("order cannot be redeemed in current status")
+	if ord.Status == order.StatusCompleted {
+		return nil, order.ErrAlreadyRedeemed
+	}
+	if !ord.Status.CanTransitionTo(order.StatusCompleted) {
+		return nil, order.ErrNotRedeemable
 	}
 
-	// Check pickup time validity (example: within 24 hours)
-	if time.Now().After(ord.PickupTime.Add(24 * time.Hour)) {
-		return errors.New("order pickup time has expired")
+	// Check pickup time validity, releasing the stock of orders nobody picked up
+	if ord.IsPickupExpired(time.Now()) {
+		if err := s.expire(ord); err != nil {
+			return nil, err
+		}
+		return nil, order.ErrPickupExpired
 	}
 
-	// Update order status
-	now := time.Now()
-	ord.Status = "completed"
 	ord.PaymentStatus = "paid"
-	ord.CompletedAt = &now
+	if err := s.transition(ord, order.StatusCompleted, order.ActorMerchant, &staffID, ""); err != nil {
+		return nil, err
+	}
+
+	return ord, nil
+}
+
+// ConfirmOrder accepts a pending order (merchant)
+func (s *orderService) ConfirmOrder(merchantID uint, staffID uint, orderID uint) error {
+	ord, err := s.findMerchantOrder(merchantID, orderID)
+	if err != nil {
+		return err
+	}
+
+	return s.transition(ord, order.StatusConfirmed, order.ActorMerchant, &staffID, "")
+}
+
+// MarkOrderReady marks a confirmed order as ready for pickup (merchant)
+func (s *orderService) MarkOrderReady(merchantID uint, staffID uint, orderID uint) error {
+	ord, err := s.findMerchantOrder(merchantID, orderID)
+	if err != nil {
+		return err
+	}
+
+	return s.transition(ord, order.StatusReady, order.ActorMerchant, &staffID, "")
+}
+
+// RejectOrder cancels an order that has not been picked up yet (merchant)
+func (s *orderService) RejectOrder(merchantID uint, staffID uint, orderID uint, reason string) error {
+	ord, err := s.findMerchantOrder(merchantID, orderID)
+	if err != nil {
+		return err
+	}
+
+	ord.CancelReason = reason
+	return s.transition(ord, order.StatusCancelled, order.ActorMerchant, &staffID, reason)
+}
+
+// CancelOrder cancels an order before the merchant has confirmed it (customer)
+func (s *orderService) CancelOrder(userID uint, orderID uint, reason string) error {
+	ord, err := s.repo.FindOrderByID(orderID)
+	if err != nil {
+		return err
+	}
+
+	// Check if order belongs to the user
+	if ord.UserID != userID {
+		return order.ErrNotOrderOwner
+	}
+
+	// Customers may only cancel orders the merchant has not started on
+	if ord.Status != order.StatusPending {
+		return order.ErrNotCancellable
+	}
+
+	ord.CancelReason = reason
+	return s.transition(ord, order.StatusCancelled, order.ActorCustomer, &userID, reason)
+}
+
+// ForceCancelOrder cancels any open order and returns its stock, recording
+// the cancellation in the admin audit log (admin)
+func (s *orderService) ForceCancelOrder(orderID uint, adminID uint, reason string) (*order.Order, error) {
+	ord, err := s.repo.FindOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	ord.CancelReason = reason
+	from := ord.Status
+	if err := ord.TransitionTo(order.StatusCancelled, time.Now()); err != nil {
+		return nil, err
+	}
+
+	history := &order.StatusHistory{
+		OrderID:    ord.ID,
+		FromStatus: from,
+		ToStatus:   order.StatusCancelled,
+		Actor:      order.ActorAdmin,
+		ActorID:    &adminID,
+		Reason:     reason,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.cancel(tx, ord, from, history); err != nil {
+			return err
+		}
+		return s.auditor.Record(tx, adminID, admin.ActionOrderCancel, admin.TargetOrder, ord.ID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ord, nil
+}
+
+// ExpireOrders cancels open orders whose pickup window has passed and
+// returns their stock. An order that fails is logged and skipped so it does
+// not hold up the rest. It returns the number of orders expired.
+func (s *orderService) ExpireOrders() (int, error) {
+	orders, err := s.repo.FindExpiredOrders(time.Now().Add(-order.PickupWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range orders {
+		err := s.expire(&orders[i])
+		if errors.Is(err, order.ErrOrderChanged) {
+			// Picked up or cancelled since it was listed
+			continue
+		}
+		if err != nil {
+			s.logger.Error("expiring order ", orders[i].ID, " failed: ", err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// expire cancels an order that was not picked up in time
+func (s *orderService) expire(ord *order.Order) error {
+	ord.CancelReason = "pickup window expired"
+	return s.transition(ord, order.StatusCancelled, order.ActorSystem, nil, ord.CancelReason)
+}
+
+// findMerchantOrder gets an order and verifies it belongs to the merchant
+func (s *orderService) findMerchantOrder(merchantID uint, orderID uint) (*order.Order, error) {
+	ord, err := s.repo.FindOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ord.MerchantID != merchantID {
+		return nil, order.ErrNotOrderOwner
+	}
+
+	return ord, nil
+}
+
+// transition moves an order to the next status and records the change with
+// the user who made it, nil for the system
+func (s *orderService) transition(ord *order.Order, next order.Status, actor string, actorID *uint, reason string) error {
+	from := ord.Status
+	if err := ord.TransitionTo(next, time.Now()); err != nil {
+		return err
+	}
+
+	history := &order.StatusHistory{
+		OrderID:    ord.ID,
+		FromStatus: from,
+		ToStatus:   next,
+		Actor:      actor,
+		ActorID:    actorID,
+		Reason:     reason,
+	}
+
+	if next != order.StatusCancelled {
+		return s.repo.UpdateOrderStatus(ord, from, history)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.cancel(tx, ord, from, history)
+	})
+}
 
-	return s.repo.UpdateOrder(ord)
+// cancel saves the cancellation of an order and gives its stock back in the
+// same transaction
+func (s *orderService) cancel(tx *gorm.DB, ord *order.Order, from order.Status, history *order.StatusHistory) error {
+	if err := s.repo.WithTrx(tx).UpdateOrderStatus(ord, from, history); err != nil {
+		return err
+	}
+	return s.releaseStock(tx, ord)
+}
+
+// releaseStock puts the quantities of an order back on its products. It does
+// nothing if the stock of the order was already released.
+func (s *orderService) releaseStock(tx *gorm.DB, ord *order.Order) error {
+	released, err := s.repo.WithTrx(tx).MarkStockReleased(ord.ID)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return nil
+	}
+
+	productRepo := s.productRepo.WithTrx(tx)
+	for _, item := range ord.Items {
+		if err := productRepo.IncrementStock(item.ProductID, item.Quantity); err != nil {
+			return err
+		}
+	}
+
+	ord.StockReleased = true
+	return nil
 }
 
 // AddToCart adds an item to cart
@@ -142,31 +422,158 @@ func (s *orderService) AddToCart(userID uint, req *order.AddToCartRequest) error
 		return err
 	}
 
-	// Verify product exists
-	prod, err := s.productRepo.FindByID(req.ProductID)
+	// Verify product exists and its shop may sell
+	prod, err := s.productRepo.FindListedByID(req.ProductID)
 	if err != nil {
-		return errors.New("product not found")
+		return err
+	}
+
+	// The merchant always comes from the product
+	if req.MerchantID != 0 && req.MerchantID != prod.MerchantID {
+		return order.ErrMerchantMismatch
 	}
 
-	// Check stock
-	if prod.Stock < req.Quantity {
-		return errors.New("insufficient stock")
+	// The repository adds to an existing line, so check the combined quantity
+	quantity := req.Quantity
+	for _, item := range cart.Items {
+		if item.ProductID == prod.ID {
+			quantity += item.Quantity
+		}
+	}
+
+	if err := checkAvailable(prod, quantity); err != nil {
+		return err
 	}
 
 	// Add item to cart
 	cartItem := &order.CartItem{
 		CartID:     cart.ID,
-		ProductID:  req.ProductID,
-		MerchantID: req.MerchantID,
+		ProductID:  prod.ID,
+		MerchantID: prod.MerchantID,
 		Quantity:   req.Quantity,
 	}
 
 	return s.repo.AddCartItem(cartItem)
 }
 
-// GetCart gets user's cart
-func (s *orderService) GetCart(userID uint) (*order.Cart, error) {
-	return s.repo.FindCartByUserID(userID)
+// checkAvailable verifies a product can be put in the cart or ordered in the
+// given quantity
+func checkAvailable(prod *product.Product, quantity int) error {
+	if !prod.IsActive || prod.IsUnlisted() {
+		return order.ErrProductInactive
+	}
+	if prod.IsExpired(time.Now()) {
+		return order.ErrProductExpired
+	}
+	if prod.Stock < quantity {
+		return order.ErrInsufficientStock
+	}
+	return nil
+}
+
+// GetCart gets user's cart grouped by shop, with current product prices and
+// items that can no longer be ordered flagged
+func (s *orderService) GetCart(userID uint) (*order.CartView, error) {
+	cart, err := s.repo.FindCartByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]uint, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products, err := s.productRepo.FindByIDs(productIDs)
+	if err != nil {
+		return nil, err
+	}
+	productsByID := make(map[uint]product.Product, len(products))
+	merchantIDs := make([]uint, 0, len(products))
+	seenMerchants := make(map[uint]bool)
+	for _, prod := range products {
+		productsByID[prod.ID] = prod
+		if !seenMerchants[prod.MerchantID] {
+			seenMerchants[prod.MerchantID] = true
+			merchantIDs = append(merchantIDs, prod.MerchantID)
+		}
+	}
+
+	merchants, err := s.merchantRepo.FindByIDs(merchantIDs)
+	if err != nil {
+		return nil, err
+	}
+	merchantsByID := make(map[uint]merchant.Merchant, len(merchants))
+	for _, merch := range merchants {
+		merchantsByID[merch.ID] = merch
+	}
+
+	view := &order.CartView{CartID: cart.ID}
+	shopIndex := make(map[uint]int)
+	now := time.Now()
+
+	for _, item := range cart.Items {
+		viewItem := order.CartViewItem{
+			ID:        item.ID,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+
+		merchantID := item.MerchantID
+		prod, found := productsByID[item.ProductID]
+		if found {
+			merchantID = prod.MerchantID
+			viewItem.Name = prod.Name
+			viewItem.OrigPrice = prod.OrigPrice
+			viewItem.SalePrice = prod.SalePrice
+			viewItem.Discount = prod.Discount
+			viewItem.Images = prod.Images
+			viewItem.ExpiryDate = prod.ExpiryDate
+			viewItem.Stock = prod.Stock
+			viewItem.Subtotal = prod.SalePrice * float64(item.Quantity)
+		}
+		viewItem.Issue = cartItemIssue(prod, found, item.Quantity, now)
+		viewItem.Available = viewItem.Issue == ""
+
+		idx, ok := shopIndex[merchantID]
+		if !ok {
+			shop := order.CartShop{}
+			if merch, ok := merchantsByID[merchantID]; ok {
+				shop.Merchant = &merch
+			}
+			view.Shops = append(view.Shops, shop)
+			idx = len(view.Shops) - 1
+			shopIndex[merchantID] = idx
+		}
+
+		shop := &view.Shops[idx]
+		shop.Items = append(shop.Items, viewItem)
+		if viewItem.Available {
+			shop.Subtotal += viewItem.Subtotal
+			view.Total += viewItem.Subtotal
+			view.ItemCount += item.Quantity
+		}
+	}
+
+	return view, nil
+}
+
+// cartItemIssue returns why a cart item can no longer be ordered, or an empty
+// string if it can
+func cartItemIssue(prod product.Product, found bool, quantity int, now time.Time) string {
+	switch {
+	case !found:
+		return order.ItemIssueUnavailable
+	case !prod.IsActive || prod.IsUnlisted():
+		return order.ItemIssueInactive
+	case prod.IsExpired(now):
+		return order.ItemIssueExpired
+	case prod.Stock <= 0:
+		return order.ItemIssueOutOfStock
+	case prod.Stock < quantity:
+		return order.ItemIssueInsufficientStock
+	}
+	return ""
 }
 
 // UpdateCartItem updates cart item quantity
@@ -185,7 +592,7 @@ func (s *orderService) UpdateCartItem(userID uint, itemID uint, quantity int) er
 
 	// Verify item belongs to user's cart
 	if item.CartID != cart.ID {
-		return errors.New("unauthorized")
+		return order.ErrNotCartOwner
 	}
 
 	// If quantity is 0, remove item
@@ -193,14 +600,14 @@ func (s *orderService) UpdateCartItem(userID uint, itemID uint, quantity int) er
 		return s.repo.RemoveCartItem(itemID)
 	}
 
-	// Verify stock
-	prod, err := s.productRepo.FindByID(item.ProductID)
+	// Verify product is still available in this quantity
+	prod, err := s.productRepo.FindListedByID(item.ProductID)
 	if err != nil {
 		return err
 	}
 
-	if prod.Stock < quantity {
-		return errors.New("insufficient stock")
+	if err := checkAvailable(prod, quantity); err != nil {
+		return err
 	}
 
 	// Update quantity
@@ -224,12 +631,122 @@ func (s *orderService) RemoveCartItem(userID uint, itemID uint) error {
 
 	// Verify item belongs to user's cart
 	if item.CartID != cart.ID {
-		return errors.New("unauthorized")
+		return order.ErrNotCartOwner
 	}
 
 	return s.repo.RemoveCartItem(itemID)
 }
 
+// Checkout places one order per merchant from the user's cart and removes the
+// checked-out items, all in a single transaction
+func (s *orderService) Checkout(userID uint, req *order.CheckoutRequest) (*order.CheckoutResponse, error) {
+	cart, err := s.repo.FindCartByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := selectCartItems(cart.Items, req.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	products, err := s.productRepo.FindByIDs(productIDs)
+	if err != nil {
+		return nil, err
+	}
+	merchantOf := make(map[uint]uint, len(products))
+	for _, prod := range products {
+		merchantOf[prod.ID] = prod.MerchantID
+	}
+
+	// Group items by the merchant of their product. The merchant stored on
+	// older cart items came from the client and cannot be trusted.
+	byMerchant := make(map[uint][]order.OrderItemRequest)
+	var merchantIDs []uint
+	var itemIDs []uint
+	for _, item := range items {
+		merchantID, ok := merchantOf[item.ProductID]
+		if !ok {
+			return nil, product.ErrProductNotFound
+		}
+		if _, ok := byMerchant[merchantID]; !ok {
+			merchantIDs = append(merchantIDs, merchantID)
+		}
+		byMerchant[merchantID] = append(byMerchant[merchantID], order.OrderItemRequest{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	// Place orders in a stable merchant order so concurrent checkouts lock
+	// product rows in the same sequence
+	sort.Slice(merchantIDs, func(i, j int) bool { return merchantIDs[i] < merchantIDs[j] })
+
+	response := &order.CheckoutResponse{}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for _, merchantID := range merchantIDs {
+			ord, err := s.placeOrder(tx, userID, &order.CreateOrderRequest{
+				MerchantID:      merchantID,
+				DeliveryAddress: req.DeliveryAddress,
+				PaymentMethod:   req.PaymentMethod,
+				Notes:           req.Notes,
+				Items:           byMerchant[merchantID],
+			})
+			if err != nil {
+				return err
+			}
+			response.Orders = append(response.Orders, *ord)
+			response.OrderCodes = append(response.OrderCodes, ord.OrderCode)
+		}
+
+		return s.repo.WithTrx(tx).RemoveCartItems(cart.ID, itemIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// selectCartItems picks the cart items to check out, or all items when no
+// IDs are given. An item selected twice would be ordered twice, so it is
+// rejected.
+func selectCartItems(items []order.CartItem, ids []uint) ([]order.CartItem, error) {
+	if len(items) == 0 {
+		return nil, order.ErrCartEmpty
+	}
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	byID := make(map[uint]order.CartItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	selected := make([]order.CartItem, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, order.ErrDuplicateCartItem
+		}
+		seen[id] = true
+
+		item, ok := byID[id]
+		if !ok {
+			return nil, order.ErrCartItemNotFound
+		}
+		selected = append(selected, item)
+	}
+
+	return selected, nil
+}
+
 // ClearCart clears user's cart
 func (s *orderService) ClearCart(userID uint) error {
 	cart, err := s.repo.FindCartByUserID(userID)
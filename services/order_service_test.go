@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/order"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// stockProductRepo keeps products in memory. ReserveStock takes the stock
+// under a lock only if enough is left, like the conditional UPDATE of the
+// real repository. Inside a transaction it also tells the database, which
+// gives the stock back on rollback.
+type stockProductRepo struct {
+	product.Repository
+
+	mu       *sync.Mutex
+	products map[uint]product.Product
+	tx       *gorm.DB
+}
+
+func newStockProductRepo(products ...product.Product) *stockProductRepo {
+	repo := &stockProductRepo{mu: &sync.Mutex{}, products: make(map[uint]product.Product)}
+	for _, prod := range products {
+		repo.products[prod.ID] = prod
+	}
+	return repo
+}
+
+func (r *stockProductRepo) WithTrx(tx *gorm.DB) product.Repository {
+	trx := *r
+	trx.tx = tx
+	return &trx
+}
+
+func (r *stockProductRepo) FindListedByID(id uint) (*product.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prod, ok := r.products[id]
+	if !ok {
+		return nil, product.ErrProductNotFound
+	}
+	return &prod, nil
+}
+
+func (r *stockProductRepo) ReserveStock(id uint, quantity int) (bool, error) {
+	r.mu.Lock()
+	prod := r.products[id]
+	if !prod.IsActive || prod.IsUnlisted() || prod.IsExpired(time.Now()) || prod.Stock < quantity {
+		r.mu.Unlock()
+		return false, nil
+	}
+	prod.Stock -= quantity
+	r.products[id] = prod
+	r.mu.Unlock()
+
+	if r.tx != nil {
+		return true, r.tx.Exec("RESERVE", id, quantity).Error
+	}
+	return true, nil
+}
+
+// release gives back stock of a rolled back reservation
+func (r *stockProductRepo) release(id uint, quantity int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prod := r.products[id]
+	prod.Stock += quantity
+	r.products[id] = prod
+}
+
+func (r *stockProductRepo) stock(id uint) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.products[id].Stock
+}
+
+func (r *stockProductRepo) FindByIDs(ids []uint) ([]product.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var products []product.Product
+	for _, id := range ids {
+		if prod, ok := r.products[id]; ok {
+			products = append(products, prod)
+		}
+	}
+	return products, nil
+}
+
+// memoryOrderRepo keeps created orders and a cart in memory
+type memoryOrderRepo struct {
+	order.Repository
+
+	mu      sync.Mutex
+	orders  []*order.Order
+	cart    order.Cart
+	removed []uint
+}
+
+func (r *memoryOrderRepo) WithTrx(*gorm.DB) order.Repository { return r }
+
+func (r *memoryOrderRepo) CreateOrder(ord *order.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ord.ID = uint(len(r.orders) + 1)
+	r.orders = append(r.orders, ord)
+	return nil
+}
+
+func (r *memoryOrderRepo) FindCartByUserID(userID uint) (*order.Cart, error) {
+	cart := r.cart
+	return &cart, nil
+}
+
+func (r *memoryOrderRepo) RemoveCartItems(cartID uint, ids []uint) error {
+	r.removed = append(r.removed, ids...)
+	return nil
+}
+
+// memoryConnector stands in for the database, so lib.Database.Transaction
+// runs against the in-memory repositories. Every connection remembers the
+// stock reserved in its open transaction and releases it on rollback.
+type memoryConnector struct {
+	release func(id uint, quantity int)
+}
+
+type memoryConn struct {
+	connector *memoryConnector
+	reserved  [][2]int64
+}
+
+func (c *memoryConnector) Connect(context.Context) (driver.Conn, error) {
+	return &memoryConn{connector: c}, nil
+}
+
+func (c *memoryConnector) Driver() driver.Driver { return c }
+
+func (c *memoryConnector) Open(string) (driver.Conn, error) { return c.Connect(context.Background()) }
+
+func (c *memoryConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *memoryConn) Close() error                        { return nil }
+
+func (c *memoryConn) Begin() (driver.Tx, error) {
+	c.reserved = nil
+	return c, nil
+}
+
+func (c *memoryConn) Commit() error {
+	c.reserved = nil
+	return nil
+}
+
+func (c *memoryConn) Rollback() error {
+	for _, r := range c.reserved {
+		c.connector.release(uint(r[0]), int(r[1]))
+	}
+	c.reserved = nil
+	return nil
+}
+
+// ExecContext records a RESERVE of the product ID and quantity
+func (c *memoryConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if query != "RESERVE" || len(args) != 2 {
+		return nil, errors.New("not supported: " + query)
+	}
+	c.reserved = append(c.reserved, [2]int64{args[0].Value.(int64), args[1].Value.(int64)})
+	return driver.RowsAffected(1), nil
+}
+
+// memoryDatabase opens a database that hands rolled back reservations to
+// release, which may be nil if nothing is reserved
+func memoryDatabase(t *testing.T, release func(id uint, quantity int)) lib.Database {
+	t.Helper()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(&memoryConnector{release: release}),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lib.Database{DB: db}
+}
+
+// item is a product ID and quantity of an order request
+type item struct {
+	productID uint
+	quantity  int
+}
+
+func orderRequest(merchantID uint, items ...item) *order.CreateOrderRequest {
+	req := &order.CreateOrderRequest{MerchantID: merchantID}
+	for _, it := range items {
+		req.Items = append(req.Items, struct {
+			ProductID uint `json:"product_id" binding:"required"`
+			Quantity  int  `json:"quantity" binding:"required,gt=0"`
+		}{it.productID, it.quantity})
+	}
+	return req
+}
+
+func TestCreateOrderConcurrentStock(t *testing.T) {
+	const (
+		stock  = 5
+		buyers = 50
+	)
+
+	products := newStockProductRepo(product.Product{
+		ID:         1,
+		MerchantID: 7,
+		Name:       "Bread bag",
+		SalePrice:  20000,
+		Stock:      stock,
+		IsActive:   true,
+		ExpiryDate: time.Now().Add(24 * time.Hour),
+	})
+	orders := &memoryOrderRepo{}
+	svc := NewOrderService(memoryDatabase(t, products.release), orders, products, nil, lib.RedeemTokenManager{}, nil, lib.Logger{})
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		succeeded  int
+		outOfStock int
+	)
+	start := make(chan struct{})
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			<-start
+			_, err := svc.CreateOrder(userID, orderRequest(7, item{1, 1}))
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, order.ErrInsufficientStock):
+				outOfStock++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(uint(i + 1))
+	}
+	close(start)
+	wg.Wait()
+
+	if succeeded != stock {
+		t.Errorf("succeeded orders = %d, want %d", succeeded, stock)
+	}
+	if outOfStock != buyers-stock {
+		t.Errorf("out of stock orders = %d, want %d", outOfStock, buyers-stock)
+	}
+	if len(orders.orders) != stock {
+		t.Errorf("created orders = %d, want %d", len(orders.orders), stock)
+	}
+	if left := products.stock(1); left != 0 {
+		t.Errorf("stock left = %d, want 0", left)
+	}
+}
+
+func TestCreateOrderReleasesStockOfFailedOrder(t *testing.T) {
+	expiry := time.Now().Add(24 * time.Hour)
+	products := newStockProductRepo(
+		product.Product{ID: 1, MerchantID: 7, Name: "Bread bag", SalePrice: 20000, Stock: 5, IsActive: true, ExpiryDate: expiry},
+		product.Product{ID: 2, MerchantID: 7, Name: "Sushi box", SalePrice: 45000, Stock: 1, IsActive: true, ExpiryDate: expiry},
+	)
+	orders := &memoryOrderRepo{}
+	svc := NewOrderService(memoryDatabase(t, products.release), orders, products, nil, lib.RedeemTokenManager{}, nil, lib.Logger{})
+
+	_, err := svc.CreateOrder(1, orderRequest(7, item{1, 2}, item{2, 3}))
+	if !errors.Is(err, order.ErrInsufficientStock) {
+		t.Fatalf("err = %v, want %v", err, order.ErrInsufficientStock)
+	}
+
+	if stock := products.stock(1); stock != 5 {
+		t.Errorf("stock of first item = %d, want 5", stock)
+	}
+	if stock := products.stock(2); stock != 1 {
+		t.Errorf("stock of second item = %d, want 1", stock)
+	}
+	if len(orders.orders) != 0 {
+		t.Errorf("created orders = %d, want 0", len(orders.orders))
+	}
+}
+
+func TestCheckoutGroupsByProductMerchant(t *testing.T) {
+	expiry := time.Now().Add(24 * time.Hour)
+	products := newStockProductRepo(
+		product.Product{ID: 1, MerchantID: 7, Name: "Bread bag", SalePrice: 20000, Stock: 5, IsActive: true, ExpiryDate: expiry},
+		product.Product{ID: 2, MerchantID: 7, Name: "Cake box", SalePrice: 30000, Stock: 5, IsActive: true, ExpiryDate: expiry},
+		product.Product{ID: 3, MerchantID: 8, Name: "Sushi box", SalePrice: 45000, Stock: 5, IsActive: true, ExpiryDate: expiry},
+	)
+	// Older cart items carry whatever merchant the client sent
+	orders := &memoryOrderRepo{cart: order.Cart{ID: 4, UserID: 1, Items: []order.CartItem{
+		{ID: 1, ProductID: 1, MerchantID: 99, Quantity: 1},
+		{ID: 2, ProductID: 2, MerchantID: 8, Quantity: 2},
+		{ID: 3, ProductID: 3, MerchantID: 8, Quantity: 1},
+	}}}
+	svc := NewOrderService(memoryDatabase(t, products.release), orders, products, nil, lib.RedeemTokenManager{}, nil, lib.Logger{})
+
+	resp, err := svc.Checkout(1, &order.CheckoutRequest{DeliveryAddress: "Q1", PaymentMethod: "cash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Orders) != 2 {
+		t.Fatalf("orders = %d, want 2", len(resp.Orders))
+	}
+	for _, ord := range resp.Orders {
+		want := map[uint]int{7: 2, 8: 1}[ord.MerchantID]
+		if len(ord.Items) != want {
+			t.Errorf("items of merchant %d = %d, want %d", ord.MerchantID, len(ord.Items), want)
+		}
+	}
+	if len(orders.removed) != 3 {
+		t.Errorf("removed cart items = %v, want all 3", orders.removed)
+	}
+}
+
+func TestSelectCartItemsRejectsDuplicates(t *testing.T) {
+	items := []order.CartItem{{ID: 1}, {ID: 2}}
+
+	if _, err := selectCartItems(items, []uint{1, 2, 1}); !errors.Is(err, order.ErrDuplicateCartItem) {
+		t.Errorf("duplicate ids: err = %v, want %v", err, order.ErrDuplicateCartItem)
+	}
+
+	selected, err := selectCartItems(items, []uint{2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].ID != 2 || selected[1].ID != 1 {
+		t.Errorf("selected = %+v, want items 2 and 1", selected)
+	}
+}
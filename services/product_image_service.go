@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/media"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/imaging"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/utils"
+	"gorm.io/gorm"
+)
+
+type productImageService struct {
+	db      lib.Database
+	repo    product.Repository
+	storage media.Storage
+	logger  lib.Logger
+}
+
+// NewProductImageService creates a new product image service
+func NewProductImageService(
+	db lib.Database,
+	repo product.Repository,
+	storage media.Storage,
+	logger lib.Logger,
+) product.ImageService {
+	return &productImageService{
+		db:      db,
+		repo:    repo,
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// AddImage stores an uploaded image and its thumbnail and appends it to the
+// images of the product
+func (s *productImageService) AddImage(productID uint, merchantID uint, upload *product.ImageUpload) (*product.Image, error) {
+	if len(upload.Data) > product.MaxImageBytes {
+		return nil, media.ErrFileTooLarge
+	}
+	if _, err := s.findMerchantProduct(s.repo, productID, merchantID); err != nil {
+		return nil, err
+	}
+
+	info, err := imaging.Inspect(upload.Data)
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return nil, media.ErrFileTooLarge.WithMessage("the image dimensions are too large")
+	}
+	if err != nil {
+		return nil, media.ErrUnsupportedImage
+	}
+	thumbnail, err := imaging.Thumbnail(upload.Data, product.ThumbnailSize)
+	if err != nil {
+		return nil, media.ErrUnsupportedImage
+	}
+
+	// Files are stored before the row is created, so a failed upload leaves
+	// no dangling image
+	name := fmt.Sprintf("products/%d/%s", productID, utils.NewTokenID())
+	image := &product.Image{
+		ProductID:    productID,
+		StorageKey:   name + "." + fileExtension(info.Format),
+		ThumbnailKey: name + "_thumb.jpg",
+		AltText:      upload.AltText,
+		Width:        info.Width,
+		Height:       info.Height,
+	}
+	if err := s.storage.Put(image.StorageKey, upload.Data, info.ContentType); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(image.ThumbnailKey, thumbnail, "image/jpeg"); err != nil {
+		s.deleteFiles(image)
+		return nil, err
+	}
+	image.URL = s.storage.URL(image.StorageKey)
+	image.ThumbnailURL = s.storage.URL(image.ThumbnailKey)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTrx(tx)
+
+		// Lock the product so concurrent uploads get distinct positions
+		if _, err := repo.LockByID(productID); err != nil {
+			return err
+		}
+
+		images, err := repo.FindImages(productID)
+		if err != nil {
+			return err
+		}
+		if len(images) >= product.MaxImagesPerProduct {
+			return product.ErrTooManyImages
+		}
+		if len(images) > 0 {
+			image.Position = images[len(images)-1].Position + 1
+		}
+
+		return repo.CreateImage(image)
+	})
+	if err != nil {
+		s.deleteFiles(image)
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// UpdateImage updates the alt text of an image
+func (s *productImageService) UpdateImage(productID uint, imageID uint, merchantID uint, req *product.UpdateImageRequest) (*product.Image, error) {
+	if _, err := s.findMerchantProduct(s.repo, productID, merchantID); err != nil {
+		return nil, err
+	}
+
+	image, err := s.repo.FindImage(productID, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	image.AltText = req.AltText
+	if err := s.repo.UpdateImage(image); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// DeleteImage deletes an image and its stored files
+func (s *productImageService) DeleteImage(productID uint, imageID uint, merchantID uint) error {
+	if _, err := s.findMerchantProduct(s.repo, productID, merchantID); err != nil {
+		return err
+	}
+
+	image, err := s.repo.FindImage(productID, imageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteImage(image.ID); err != nil {
+		return err
+	}
+	s.deleteFiles(image)
+
+	return nil
+}
+
+// ReorderImages sets the order of all images of a product
+func (s *productImageService) ReorderImages(productID uint, merchantID uint, req *product.ReorderImagesRequest) ([]product.Image, error) {
+	var images []product.Image
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTrx(tx)
+
+		if _, err := repo.LockByID(productID); err != nil {
+			return err
+		}
+		if _, err := s.findMerchantProduct(repo, productID, merchantID); err != nil {
+			return err
+		}
+
+		var err error
+		images, err = repo.FindImages(productID)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[uint]*product.Image, len(images))
+		for i := range images {
+			byID[images[i].ID] = &images[i]
+		}
+		if len(req.ImageIDs) != len(images) {
+			return product.ErrInvalidImageOrder
+		}
+
+		ordered := make([]product.Image, 0, len(images))
+		for position, id := range req.ImageIDs {
+			image, ok := byID[id]
+			if !ok {
+				return product.ErrInvalidImageOrder
+			}
+			delete(byID, id)
+
+			if image.Position != position {
+				if err := repo.SetImagePosition(id, position); err != nil {
+					return err
+				}
+				image.Position = position
+			}
+			ordered = append(ordered, *image)
+		}
+		images = ordered
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// findMerchantProduct gets a product and verifies it belongs to the merchant
+func (s *productImageService) findMerchantProduct(repo product.Repository, productID uint, merchantID uint) (*product.Product, error) {
+	prod, err := repo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if prod.MerchantID != merchantID {
+		return nil, product.ErrNotProductOwner
+	}
+
+	return prod, nil
+}
+
+// deleteFiles removes the stored files of an image once its row is gone.
+// Nothing links to a file left behind, so errors are just logged.
+func (s *productImageService) deleteFiles(image *product.Image) {
+	for _, key := range []string{image.StorageKey, image.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(key); err != nil {
+			s.logger.Error("deleting stored image failed: ", err)
+		}
+	}
+}
+
+// fileExtension gives the file extension of an image format
+func fileExtension(format string) string {
+	if format == "jpeg" {
+		return "jpg"
+	}
+	return format
+}
@@ -2,23 +2,54 @@ package services
 
 import (
 	"errors"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/admin"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/auth"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/category"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/location"
 	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib/pagination"
+	"gorm.io/gorm"
+	"math"
 	"time"
 )
 
 type productService struct {
-	repo product.Repository
+	db           lib.Database
+	repo         product.Repository
+	locationRepo location.Repository
+	categories   category.Service
+	index        product.SearchIndex
+	auditor      admin.Auditor
+	logger       lib.Logger
 }
 
 // NewProductService creates a new product service
-func NewProductService(repo product.Repository) product.Service {
-	return &productService{repo: repo}
+func NewProductService(
+	db lib.Database,
+	repo product.Repository,
+	locationRepo location.Repository,
+	categories category.Service,
+	index product.SearchIndex,
+	auditor admin.Auditor,
+	logger lib.Logger,
+) product.Service {
+	return &productService{
+		db:           db,
+		repo:         repo,
+		locationRepo: locationRepo,
+		categories:   categories,
+		index:        index,
+		auditor:      auditor,
+		logger:       logger,
+	}
 }
 
 // CreateProduct creates a new product
-func (s *productService) CreateProduct(merchantID uint, req *product.CreateProductRequest) (*product.Product, error) {
-	// Calculate discount
-	discount := ((req.OrigPrice - req.SalePrice) / req.OrigPrice) * 100
+func (s *productService) CreateProduct(merchantID uint, staffID uint, req *product.CreateProductRequest) (*product.Product, error) {
+	if err := s.categories.CheckAssignable(req.Category); err != nil {
+		return nil, err
+	}
 
 	prod := &product.Product{
 		MerchantID:  merchantID,
@@ -27,83 +58,274 @@ func (s *productService) CreateProduct(merchantID uint, req *product.CreateProdu
 		Category:    req.Category,
 		OrigPrice:   req.OrigPrice,
 		SalePrice:   req.SalePrice,
-		Discount:    discount,
+		Discount:    discountPercent(req.OrigPrice, req.SalePrice),
 		Stock:       req.Stock,
-		Images:      req.Images,
 		ExpiryDate:  req.ExpiryDate,
 		IsActive:    true,
 	}
 
-	if err := s.repo.Create(prod); err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTrx(tx)
+		if err := repo.Create(prod); err != nil {
+			return err
+		}
+		return repo.RecordPriceChange(&product.PriceChange{
+			ProductID:    prod.ID,
+			MerchantID:   merchantID,
+			NewOrigPrice: prod.OrigPrice,
+			NewSalePrice: prod.SalePrice,
+			Actor:        product.PriceActorMerchant,
+			ActorID:      &staffID,
+			Reason:       product.PriceReasonCreated,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.reindex(prod)
 
 	return prod, nil
 }
 
-// GetProductByID gets a product by ID
+// GetProductByID gets a publicly listed product by ID. Products of
+// unverified merchants are not found.
 func (s *productService) GetProductByID(id uint) (*product.Product, error) {
-	return s.repo.FindByID(id)
+	return s.repo.FindListedByID(id)
 }
 
 // SearchProducts searches for products with filters
-func (s *productService) SearchProducts(filter *product.SearchFilter) ([]product.Product, int64, error) {
-	// Set default limit if not provided
-	if filter.Limit == 0 {
-		filter.Limit = 20
+func (s *productService) SearchProducts(filter *product.SearchFilter) (*product.SearchResult, error) {
+	// Set default page size if not provided
+	if filter.Page.Limit <= 0 {
+		filter.Page.Limit = pagination.DefaultLimit
 	}
 
-	return s.repo.FindAll(filter)
-}
+	if err := s.resolveNear(filter); err != nil {
+		return nil, err
+	}
 
-// UpdateProduct updates a product
-func (s *productService) UpdateProduct(id uint, merchantID uint, req *product.UpdateProductRequest) error {
-	// Get existing product
-	prod, err := s.repo.FindByID(id)
+	if filter.MinDiscount < 0 || filter.MinDiscount > 100 {
+		return nil, product.ErrInvalidFilter.WithMessage("min_discount must be between 0 and 100")
+	}
+	if filter.ExpiringWithin < 0 {
+		return nil, product.ErrInvalidFilter.WithMessage("expiring_within must not be negative")
+	}
+
+	// A category also matches the products of its subcategories
+	if filter.Category != "" {
+		slugs, err := s.categories.SubtreeSlugs(filter.Category)
+		if errors.Is(err, category.ErrCategoryNotFound) {
+			return nil, product.ErrInvalidFilter.WithMessage("unknown category")
+		}
+		if err != nil {
+			return nil, err
+		}
+		filter.Categories = slugs
+	}
+
+	if filter.SortBy == "" && filter.Keyword != "" {
+		filter.SortBy = product.SortRelevance
+	}
+	if filter.SortBy == product.SortRelevance && filter.Keyword == "" {
+		filter.SortBy = product.SortNewest
+	}
+
+	switch filter.SortBy {
+	case "", product.SortNewest, product.SortRelevance, product.SortDiscount,
+		product.SortPriceAsc, product.SortPriceDesc, product.SortExpiry:
+	case product.SortDistance:
+		if filter.Near == nil {
+			return nil, product.ErrLocationRequired
+		}
+	default:
+		return nil, product.ErrInvalidSort
+	}
+
+	if filter.Keyword != "" {
+		// Take every hit: filters, counts and pages are applied by the
+		// database, so hits cut off here would never show up on any page
+		hits, err := s.index.Search(filter.Keyword, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(hits) == 0 {
+			return emptySearchResult(filter.Page.After == nil), nil
+		}
+
+		filter.ProductIDs = make([]uint, len(hits))
+		for i, hit := range hits {
+			filter.ProductIDs[i] = hit.ProductID
+		}
+	}
+
+	products, next, err := s.repo.FindAll(filter)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Check ownership
-	if prod.MerchantID != merchantID {
-		return errors.New("unauthorized")
+	result := &product.SearchResult{
+		Products:   products,
+		NextCursor: next,
 	}
 
-	// Update fields
-	if req.Name != "" {
-		prod.Name = req.Name
+	// Later pages only carry products
+	if filter.Page.After == nil {
+		total, err := s.repo.Count(filter)
+		if err != nil {
+			return nil, err
+		}
+		facets, err := s.repo.Facets(filter)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = &total
+		result.Facets = facets
 	}
-	if req.Description != "" {
-		prod.Description = req.Description
+
+	return result, nil
+}
+
+// emptySearchResult is the result of a keyword without any hit
+func emptySearchResult(firstPage bool) *product.SearchResult {
+	result := &product.SearchResult{Products: []product.Product{}}
+	if !firstPage {
+		return result
 	}
-	if req.Category != "" {
-		prod.Category = req.Category
+
+	facets := &product.Facets{
+		Categories:  []product.CategoryFacet{},
+		PriceRanges: make([]product.PriceFacet, len(product.PriceBuckets)),
 	}
-	if req.OrigPrice > 0 {
-		prod.OrigPrice = req.OrigPrice
+	for i, bucket := range product.PriceBuckets {
+		facets.PriceRanges[i].PriceRange = bucket
 	}
-	if req.SalePrice > 0 {
-		prod.SalePrice = req.SalePrice
+
+	var total int64
+	result.Total = &total
+	result.Facets = facets
+
+	return result
+}
+
+// resolveNear fills the search point from a saved location and validates
+// the point and radius
+func (s *productService) resolveNear(filter *product.SearchFilter) error {
+	if filter.Near == nil && filter.LocationID > 0 {
+		if filter.UserID == 0 {
+			return auth.ErrUnauthenticated
+		}
+
+		loc, err := s.locationRepo.FindByID(filter.LocationID)
+		if err != nil {
+			return err
+		}
+		if loc.UserID != filter.UserID {
+			return location.ErrNotLocationOwner
+		}
+
+		filter.Near = &product.GeoPoint{Latitude: loc.Latitude, Longitude: loc.Longitude}
 	}
-	if req.Stock >= 0 {
-		prod.Stock = req.Stock
+
+	if filter.Near == nil {
+		return nil
 	}
-	if req.Images != "" {
-		prod.Images = req.Images
+
+	if !filter.Near.Valid() {
+		return product.ErrInvalidCoordinates
 	}
-	if !req.ExpiryDate.IsZero() {
-		prod.ExpiryDate = req.ExpiryDate
+	if filter.RadiusKm == 0 {
+		filter.RadiusKm = product.DefaultRadiusKm
 	}
-	prod.IsActive = req.IsActive
+	if filter.RadiusKm < 0 || filter.RadiusKm > product.MaxRadiusKm {
+		return product.ErrInvalidRadius
+	}
+
+	return nil
+}
 
-	// Recalculate discount
-	if prod.OrigPrice > 0 && prod.SalePrice > 0 {
-		prod.Discount = ((prod.OrigPrice - prod.SalePrice) / prod.OrigPrice) * 100
+// UpdateProduct updates a product. A change of its prices is recorded in
+// the price history.
+func (s *productService) UpdateProduct(id uint, merchantID uint, staffID uint, req *product.UpdateProductRequest) error {
+	if req.Category != "" {
+		if err := s.categories.CheckAssignable(req.Category); err != nil {
+			return err
+		}
 	}
 
-	prod.UpdatedAt = time.Now()
+	var prod *product.Product
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTrx(tx)
+
+		// Lock the product so the recorded old prices are the ones replaced
+		var err error
+		prod, err = repo.LockByID(id)
+		if err != nil {
+			return err
+		}
+
+		// Check ownership
+		if prod.MerchantID != merchantID {
+			return product.ErrNotProductOwner
+		}
+
+		oldOrigPrice, oldSalePrice := prod.OrigPrice, prod.SalePrice
+
+		// Update fields
+		if req.Name != "" {
+			prod.Name = req.Name
+		}
+		if req.Description != "" {
+			prod.Description = req.Description
+		}
+		if req.Category != "" {
+			prod.Category = req.Category
+		}
+		if req.OrigPrice > 0 {
+			prod.OrigPrice = req.OrigPrice
+		}
+		if req.SalePrice > 0 {
+			prod.SalePrice = req.SalePrice
+		}
+		if req.Stock >= 0 {
+			prod.Stock = req.Stock
+		}
+		if !req.ExpiryDate.IsZero() {
+			prod.ExpiryDate = req.ExpiryDate
+		}
+		prod.IsActive = req.IsActive
+
+		// Recalculate discount
+		if prod.OrigPrice > 0 && prod.SalePrice > 0 {
+			prod.Discount = discountPercent(prod.OrigPrice, prod.SalePrice)
+		}
+
+		prod.UpdatedAt = time.Now()
+
+		if err := repo.Update(prod); err != nil {
+			return err
+		}
+
+		if prod.OrigPrice == oldOrigPrice && prod.SalePrice == oldSalePrice {
+			return nil
+		}
+		return repo.RecordPriceChange(&product.PriceChange{
+			ProductID:    prod.ID,
+			MerchantID:   prod.MerchantID,
+			OldOrigPrice: oldOrigPrice,
+			NewOrigPrice: prod.OrigPrice,
+			OldSalePrice: oldSalePrice,
+			NewSalePrice: prod.SalePrice,
+			Actor:        product.PriceActorMerchant,
+			ActorID:      &staffID,
+			Reason:       product.PriceReasonManual,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	s.reindex(prod)
 
-	return s.repo.Update(prod)
+	return nil
 }
 
 // DeleteProduct deletes a product
@@ -116,18 +338,249 @@ func (s *productService) DeleteProduct(id uint, merchantID uint) error {
 
 	// Check ownership
 	if prod.MerchantID != merchantID {
-		return errors.New("unauthorized")
+		return product.ErrNotProductOwner
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	if err := s.index.Remove(id); err != nil {
+		s.logger.Error("removing product from search index failed: ", err)
+	}
+
+	return nil
+}
+
+// UnlistProduct takes a product down from the public listing and records it
+// in the admin audit log (admin)
+func (s *productService) UnlistProduct(adminID uint, id uint, reason string) (*product.Product, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		unlisted, err := s.repo.WithTrx(tx).Unlist(id, reason, time.Now())
+		if err != nil {
+			return err
+		}
+		if !unlisted {
+			return product.ErrAlreadyUnlisted
+		}
+		return s.auditor.Record(tx, adminID, admin.ActionProductUnlist, admin.TargetProduct, id, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.index.Remove(id); err != nil {
+		s.logger.Error("removing product from search index failed: ", err)
 	}
 
-	return s.repo.Delete(id)
+	return s.repo.FindByID(id)
+}
+
+// RelistProduct puts a product taken down by an admin back and records it in
+// the admin audit log (admin)
+func (s *productService) RelistProduct(adminID uint, id uint) (*product.Product, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		relisted, err := s.repo.WithTrx(tx).Relist(id)
+		if err != nil {
+			return err
+		}
+		if !relisted {
+			return product.ErrNotUnlisted
+		}
+		return s.auditor.Record(tx, adminID, admin.ActionProductRelist, admin.TargetProduct, id, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prod, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.reindex(prod)
+
+	return prod, nil
 }
 
-// GetMerchantProducts gets all products for a merchant
-func (s *productService) GetMerchantProducts(merchantID uint) ([]product.Product, error) {
-	return s.repo.FindByMerchantID(merchantID)
+// reindex updates the search index after a product write. The index is
+// reloaded from the database every refresh, which repairs a missed update.
+func (s *productService) reindex(prod *product.Product) {
+	if err := s.index.Index(prod); err != nil {
+		s.logger.Error("indexing product failed: ", err)
+	}
+}
+
+// GetMerchantProducts gets a page of products for a merchant
+func (s *productService) GetMerchantProducts(merchantID uint, page pagination.Page) ([]product.Product, string, error) {
+	return s.repo.FindByMerchantID(merchantID, page)
+}
+
+// GetPriceHistory gets a page of the price changes of a merchant's product
+func (s *productService) GetPriceHistory(id uint, merchantID uint, page pagination.Page) ([]product.PriceChange, string, error) {
+	prod, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if prod.MerchantID != merchantID {
+		return nil, "", product.ErrNotProductOwner
+	}
+
+	return s.repo.FindPriceHistory(id, page)
 }
 
 // UpdateStock updates product stock
 func (s *productService) UpdateStock(id uint, quantity int) error {
 	return s.repo.UpdateStock(id, quantity)
 }
+
+// GetMarkdownRules gets the markdown rules of a merchant
+func (s *productService) GetMarkdownRules(merchantID uint) ([]product.MarkdownRule, error) {
+	return s.repo.FindMarkdownRules(merchantID)
+}
+
+// SetMarkdownRules replaces the markdown rules of a merchant
+func (s *productService) SetMarkdownRules(merchantID uint, req *product.SetMarkdownRulesRequest) ([]product.MarkdownRule, error) {
+	rules := make([]product.MarkdownRule, 0, len(req.Rules))
+	seen := make(map[int]bool, len(req.Rules))
+	for _, input := range req.Rules {
+		if seen[input.HoursBeforeExpiry] {
+			return nil, product.ErrInvalidMarkdownRule
+		}
+		seen[input.HoursBeforeExpiry] = true
+
+		rules = append(rules, product.MarkdownRule{
+			MerchantID:        merchantID,
+			HoursBeforeExpiry: input.HoursBeforeExpiry,
+			DiscountPercent:   input.DiscountPercent,
+		})
+	}
+
+	if err := s.repo.ReplaceMarkdownRules(merchantID, rules); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindMarkdownRules(merchantID)
+}
+
+// ExpireProducts deactivates active products past their expiry date. It runs
+// in the product:expire command, outside the server holding the search
+// index, so the index is left alone: search results are filtered by the
+// database and the index drops the products on its next reload. It returns
+// the number of products expired.
+func (s *productService) ExpireProducts() (int, error) {
+	now := time.Now()
+	products, err := s.repo.FindExpiringBefore(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, prod := range products {
+		deactivated, err := s.repo.DeactivateExpired(prod.ID, now)
+		if err != nil {
+			return expired, err
+		}
+		if deactivated {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// ApplyMarkdowns lowers the sale price of products that reached a markdown
+// rule of their merchant. The deepest due rule wins and a sale price is
+// never raised. It returns the number of products marked down.
+func (s *productService) ApplyMarkdowns() (int, error) {
+	rules, err := s.repo.FindAllMarkdownRules()
+	if err != nil {
+		return 0, err
+	}
+
+	byMerchant := make(map[uint][]product.MarkdownRule)
+	maxHours := 0
+	for _, rule := range rules {
+		byMerchant[rule.MerchantID] = append(byMerchant[rule.MerchantID], rule)
+		if rule.HoursBeforeExpiry > maxHours {
+			maxHours = rule.HoursBeforeExpiry
+		}
+	}
+	if maxHours == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	products, err := s.repo.FindExpiringBefore(now.Add(time.Duration(maxHours) * time.Hour))
+	if err != nil {
+		return 0, err
+	}
+
+	markedDown := 0
+	for i := range products {
+		prod := &products[i]
+		if prod.IsExpired(now) {
+			continue
+		}
+
+		price, ok := markdownPrice(prod, byMerchant[prod.MerchantID], now)
+		if !ok {
+			continue
+		}
+
+		from := prod.SalePrice
+		prod.SalePrice = price
+		prod.Discount = discountPercent(prod.OrigPrice, price)
+
+		updated, err := s.repo.UpdateSalePrice(prod, from, &product.PriceChange{
+			ProductID:    prod.ID,
+			MerchantID:   prod.MerchantID,
+			OldOrigPrice: prod.OrigPrice,
+			NewOrigPrice: prod.OrigPrice,
+			OldSalePrice: from,
+			NewSalePrice: price,
+			Actor:        product.PriceActorSystem,
+			Reason:       product.PriceReasonMarkdown,
+		})
+		if err != nil {
+			return markedDown, err
+		}
+		if !updated {
+			continue
+		}
+		markedDown++
+	}
+
+	return markedDown, nil
+}
+
+// markdownPrice gives the sale price of the deepest due markdown rule. It
+// reports false if no rule is due or none lowers the current sale price.
+func markdownPrice(prod *product.Product, rules []product.MarkdownRule, now time.Time) (float64, bool) {
+	deepest := 0.0
+	for i := range rules {
+		if rules[i].Applies(prod.ExpiryDate, now) && rules[i].DiscountPercent > deepest {
+			deepest = rules[i].DiscountPercent
+		}
+	}
+	if deepest == 0 {
+		return 0, false
+	}
+
+	price := math.Round(prod.OrigPrice * (100 - deepest) / 100)
+	if price <= 0 || price >= prod.SalePrice {
+		return 0, false
+	}
+	return price, true
+}
+
+// discountPercent gives the discount of a sale price off the original price
+func discountPercent(origPrice float64, salePrice float64) float64 {
+	return ((origPrice - salePrice) / origPrice) * 100
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/domains/product"
+	"github.com/vhakHCMUS/SMARTKET-GO-CLEAN-ARC/lib"
+)
+
+// rankedIndex returns hits for the products 1..n, best match first
+type rankedIndex struct {
+	product.SearchIndex
+
+	n int
+}
+
+func (x rankedIndex) Search(query string, limit int) ([]product.SearchHit, error) {
+	hits := make([]product.SearchHit, 0, x.n)
+	for id := 1; id <= x.n; id++ {
+		hits = append(hits, product.SearchHit{ProductID: uint(id), Score: float64(x.n - id)})
+	}
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// filteringProductRepo keeps only the keyword hits that are in stock, like
+// the database would
+type filteringProductRepo struct {
+	product.Repository
+
+	inStock map[uint]bool
+}
+
+func (r filteringProductRepo) FindAll(filter *product.SearchFilter) ([]product.Product, string, error) {
+	products := []product.Product{}
+	for _, id := range filter.ProductIDs {
+		if r.inStock[id] {
+			products = append(products, product.Product{ID: id, Stock: 1})
+		}
+	}
+	return products, "", nil
+}
+
+func (r filteringProductRepo) Count(filter *product.SearchFilter) (int64, error) {
+	products, _, err := r.FindAll(filter)
+	return int64(len(products)), err
+}
+
+func (r filteringProductRepo) Facets(*product.SearchFilter) (*product.Facets, error) {
+	return &product.Facets{}, nil
+}
+
+func TestSearchProductsKeepsLowRankedHits(t *testing.T) {
+	repo := filteringProductRepo{inStock: map[uint]bool{5000: true}}
+	svc := NewProductService(lib.Database{}, repo, nil, nil, rankedIndex{n: 5000}, nil, lib.Logger{})
+
+	result, err := svc.SearchProducts(&product.SearchFilter{Keyword: "bread", InStock: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Products) != 1 || result.Products[0].ID != 5000 {
+		t.Errorf("products = %+v, want product 5000", result.Products)
+	}
+	if result.Total == nil || *result.Total != 1 {
+		t.Errorf("total = %v, want 1", result.Total)
+	}
+}
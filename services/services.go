@@ -8,7 +8,15 @@ var Module = fx.Options(
 	fx.Provide(NewJWTAuthService),
 	fx.Provide(NewAuthService),
 	fx.Provide(NewProductService),
+	fx.Provide(NewProductImageService),
 	fx.Provide(NewMerchantService),
+	fx.Provide(NewMerchantVerificationService),
+	fx.Provide(NewMerchantStaffService),
 	fx.Provide(NewOrderService),
 	fx.Provide(NewLocationService),
+	fx.Provide(NewGeocodingService),
+	fx.Provide(NewCategoryService),
+	fx.Provide(NewNotificationService),
+	fx.Provide(NewAdminAuditor),
+	fx.Provide(NewAdminService),
 )